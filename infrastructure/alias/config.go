@@ -0,0 +1,18 @@
+package alias
+
+import "time"
+
+const (
+	CooldownFieldName = "login.alias.cooldown"
+
+	CooldownDefault = time.Duration(0)
+)
+
+type Config struct {
+	// Cooldown period after rename while former login can't be claimed by another login, zero disables cooldown
+	Cooldown time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
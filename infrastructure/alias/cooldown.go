@@ -0,0 +1,80 @@
+package alias
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	stdTime "time"
+)
+
+// Cooldown holding former logins of renamed logins for their owners during Config.Cooldown
+type Cooldown struct {
+	config  *Config
+	aliases repository.Aliases
+	tracer  trace.Tracer
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Cooldown {
+	configurator.SetDefault(CooldownFieldName, CooldownDefault)
+
+	if cooldown := configurator.GetDuration(CooldownFieldName); cooldown > 0 && config.Cooldown == CooldownDefault {
+		config.Cooldown = cooldown
+	}
+
+	return NewCooldown(config, repository, tracer, logger)
+}
+
+func NewCooldown(config *Config, aliases repository.Aliases, tracer trace.Tracer, logger log.Logger) *Cooldown {
+	logger.Infof("login.alias: cooldown - %s", config.Cooldown)
+
+	return &Cooldown{config: config, aliases: aliases, tracer: tracer}
+}
+
+// WithAliases returns copy of cooldown reading aliases from another repository, e.g. scoped by transaction
+func (cooldown *Cooldown) WithAliases(aliases repository.Aliases) *Cooldown {
+	return &Cooldown{config: cooldown.config, aliases: aliases, tracer: cooldown.tracer}
+}
+
+// Held returns alias holding login for another owner and time of its release, nil if owner can claim the login
+func (cooldown *Cooldown) Held(ctx context.Context, login string, owner uuid.UUID) (*repository.Alias, *stdTime.Time, error) {
+	if cooldown.config.Cooldown <= 0 {
+		return nil, nil, nil
+	}
+
+	ctx, span := cooldown.tracer.Start(ctx, "Held")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("login", login))
+
+	alias, err := cooldown.aliases.FindAlias(ctx, login)
+	if err == db.RecordNotFoundError {
+		return nil, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if alias.LoginUuid == owner {
+		return nil, nil, nil
+	}
+
+	releasedAt := alias.CreatedAt.Add(cooldown.config.Cooldown)
+	if !releasedAt.After(time.NowUTC()) {
+		return nil, nil, nil
+	}
+
+	return alias, &releasedAt, nil
+}
@@ -0,0 +1,70 @@
+package alias
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	"time"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+func TestCooldown_Held(t *testing.T) {
+	memory := repository.NewMemory(testTracer)
+	ctx := context.Background()
+
+	login, err := memory.Insert(ctx, &repository.Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login.Login = "bob"
+	if _, err := memory.Update(ctx, login); err != nil {
+		t.Fatal(err)
+	}
+
+	cooldown := NewCooldown(&Config{Cooldown: time.Hour}, memory, testTracer, testLogger{t})
+
+	alias, releasedAt, err := cooldown.Held(ctx, "ALICE", uuid.Nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if alias == nil || alias.LoginUuid != login.Uuid {
+		t.Fatal("former login isn't held for another owner")
+	}
+
+	if expected := alias.CreatedAt.Add(time.Hour); !releasedAt.Equal(expected) {
+		t.Fatalf("former login is released at %s, expected %s", releasedAt, expected)
+	}
+
+	if alias, _, err := cooldown.Held(ctx, "alice", login.Uuid); err != nil || alias != nil {
+		t.Fatalf("former login is held for its owner: %v", err)
+	}
+
+	disabled := NewCooldown(&Config{}, memory, testTracer, testLogger{t})
+	if alias, _, err := disabled.Held(ctx, "alice", uuid.Nil); err != nil || alias != nil {
+		t.Fatalf("former login is held by disabled cooldown: %v", err)
+	}
+
+	if alias, _, err := cooldown.Held(ctx, "carol", uuid.Nil); err != nil || alias != nil {
+		t.Fatalf("login without alias is held: %v", err)
+	}
+}
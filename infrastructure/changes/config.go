@@ -0,0 +1,26 @@
+package changes
+
+import "time"
+
+const (
+	IntervalFieldName  = "changes.interval"
+	HeartbeatFieldName = "changes.heartbeat"
+	BatchSizeFieldName = "changes.batch_size"
+
+	IntervalDefault  = time.Second
+	HeartbeatDefault = 15 * time.Second
+	BatchSizeDefault = uint(100)
+)
+
+type Config struct {
+	// Interval between checks of new changes by every stream
+	Interval time.Duration
+	// Heartbeat period without changes after which stream is told to keep connection alive
+	Heartbeat time.Duration
+	// BatchSize max count of changes read by one check
+	BatchSize uint
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
@@ -0,0 +1,145 @@
+package changes
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	stdTime "time"
+)
+
+// Feed streaming changes of logins in order of their sequence numbers, sequences of events of outbox,
+// so a client can resume the stream after the last received change
+type Feed struct {
+	config *Config
+	outbox repository.Outbox
+	tracer trace.Tracer
+	logger log.Logger
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Feed {
+	configurator.SetDefault(IntervalFieldName, IntervalDefault)
+	configurator.SetDefault(HeartbeatFieldName, HeartbeatDefault)
+	configurator.SetDefault(BatchSizeFieldName, BatchSizeDefault)
+
+	if interval := configurator.GetDuration(IntervalFieldName); interval > 0 && config.Interval == IntervalDefault {
+		config.Interval = interval
+	}
+
+	if heartbeat := configurator.GetDuration(HeartbeatFieldName); heartbeat > 0 && config.Heartbeat == HeartbeatDefault {
+		config.Heartbeat = heartbeat
+	}
+
+	if batchSize := configurator.GetUint(BatchSizeFieldName); batchSize > 0 && config.BatchSize == BatchSizeDefault {
+		config.BatchSize = batchSize
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = IntervalDefault
+	}
+
+	if config.Heartbeat <= 0 {
+		config.Heartbeat = HeartbeatDefault
+	}
+
+	if config.BatchSize == 0 {
+		config.BatchSize = BatchSizeDefault
+	}
+
+	return NewFeed(config, repository, tracer, logger)
+}
+
+func NewFeed(config *Config, outbox repository.Outbox, tracer trace.Tracer, logger log.Logger) *Feed {
+	logger.Infof(
+		"changes: interval - %s, heartbeat - %s, batch size - %d",
+		config.Interval,
+		config.Heartbeat,
+		config.BatchSize,
+	)
+
+	return &Feed{config: config, outbox: outbox, tracer: tracer, logger: logger}
+}
+
+// Last returns sequence number of the latest change, streaming after it sends only new changes
+func (feed *Feed) Last(ctx context.Context) (int64, error) {
+	return feed.outbox.LastSequence(ctx)
+}
+
+// Stream calling fn for every change with sequence number greater than after in order of the numbers
+// until ctx is done or fn fails, idle is called after every Config.Heartbeat without changes
+func (feed *Feed) Stream(
+	ctx context.Context,
+	after int64,
+	fn func(event *repository.Event) error,
+	idle func() error,
+) error {
+	ticker := stdTime.NewTicker(feed.config.Interval)
+	defer ticker.Stop()
+
+	lastSent := time.NowUTC()
+
+	for {
+		events, err := feed.next(ctx, after)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+
+			after = event.Sequence
+		}
+
+		if len(events) > 0 {
+			lastSent = time.NowUTC()
+
+			// the next batch may be ready already
+			if uint(len(events)) == feed.config.BatchSize {
+				continue
+			}
+		}
+
+		if time.NowUTC().Sub(lastSent) >= feed.config.Heartbeat {
+			if err := idle(); err != nil {
+				return err
+			}
+
+			lastSent = time.NowUTC()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// next returns changes after the sequence number, sequence numbers are taken in order of commits,
+// so no change can appear later before the returned ones
+func (feed *Feed) next(ctx context.Context, after int64) ([]*repository.Event, error) {
+	ctx, span := feed.tracer.Start(ctx, "next")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("after", after))
+
+	events, err := feed.outbox.EventsAfter(ctx, after, feed.config.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("count", len(events)))
+
+	return events, nil
+}
@@ -0,0 +1,140 @@
+package changes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	stdTime "time"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+// stopped returned by fn of Stream to stop the stream of test
+var stopped = errors.New("stopped")
+
+// insert inserting logins by ctx
+func insert(t *testing.T, ctx context.Context, memory repository.Repository, logins ...string) {
+	t.Helper()
+
+	for _, login := range logins {
+		if _, err := memory.Insert(ctx, &repository.Login{Login: login}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newTestFeed returns feed of outbox reading changes by batches of 2
+func newTestFeed(t *testing.T, outbox repository.Outbox) *Feed {
+	return NewFeed(
+		&Config{Interval: stdTime.Millisecond, Heartbeat: stdTime.Hour, BatchSize: 2},
+		outbox,
+		testTracer,
+		testLogger{t},
+	)
+}
+
+// collect returns logins of count changes streamed by feed after sequence
+func collect(t *testing.T, ctx context.Context, feed *Feed, after int64, count int) []string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*stdTime.Second)
+	defer cancel()
+
+	var logins []string
+	sequence := after
+
+	err := feed.Stream(ctx, after, func(event *repository.Event) error {
+		if event.Sequence <= sequence {
+			return fmt.Errorf("sequence %d after %d", event.Sequence, sequence)
+		}
+
+		sequence = event.Sequence
+
+		login := &repository.EventLogin{}
+		if err := json.Unmarshal([]byte(event.Payload), login); err != nil {
+			return err
+		}
+
+		logins = append(logins, login.Login)
+		if len(logins) == count {
+			return stopped
+		}
+
+		return nil
+	}, func() error {
+		return nil
+	})
+	if err != stopped {
+		t.Fatalf("stream: %v, received %v", err, logins)
+	}
+
+	return logins
+}
+
+func TestFeed_Stream(t *testing.T) {
+	ctx := context.Background()
+	memory := repository.NewMemory(testTracer)
+	feed := newTestFeed(t, memory)
+
+	insert(t, ctx, memory, "alice", "bob", "carol")
+
+	if logins := collect(t, ctx, feed, 0, 3); fmt.Sprint(logins) != "[alice bob carol]" {
+		t.Fatalf("streamed %v, expected [alice bob carol]", logins)
+	}
+
+	last, err := feed.Last(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// stream resumed after the last change sends only new ones
+	go func() {
+		stdTime.Sleep(10 * stdTime.Millisecond)
+
+		if _, err := memory.Insert(ctx, &repository.Login{Login: "dave"}); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	if logins := collect(t, ctx, feed, last, 1); fmt.Sprint(logins) != "[dave]" {
+		t.Fatalf("streamed %v after %d, expected [dave]", logins, last)
+	}
+}
+
+func TestFeed_StreamTenant(t *testing.T) {
+	ctx := context.Background()
+	acme := repository.WithTenant(ctx, "acme")
+	memory := repository.NewMemory(testTracer)
+	feed := newTestFeed(t, memory)
+
+	insert(t, ctx, memory, "alice")
+	insert(t, acme, memory, "bob", "carol")
+	insert(t, ctx, memory, "dave")
+
+	if logins := collect(t, ctx, feed, 0, 2); fmt.Sprint(logins) != "[alice dave]" {
+		t.Fatalf("streamed %v to default tenant, expected [alice dave]", logins)
+	}
+
+	if logins := collect(t, acme, feed, 0, 2); fmt.Sprint(logins) != "[bob carol]" {
+		t.Fatalf("streamed %v to tenant acme, expected [bob carol]", logins)
+	}
+}
@@ -1,14 +1,52 @@
 package container
 
 import (
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/changes"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/Diez37/logins/infrastructure/database/postgres"
+	"github.com/Diez37/logins/infrastructure/expirer"
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/Diez37/logins/infrastructure/metadata"
+	"github.com/Diez37/logins/infrastructure/migrator"
+	"github.com/Diez37/logins/infrastructure/outbox"
+	"github.com/Diez37/logins/infrastructure/policy"
 	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/reserved"
+	"github.com/Diez37/logins/infrastructure/webhook"
 	"github.com/diez37/go-packages/container"
 	"github.com/go-playground/validator/v10"
 )
 
 func AddProvide(container container.Container) error {
 	return container.Provides(
-		repository.NewSql,
+		postgres.NewConfig,
+		database.WithConfigurator,
+		database.NewReplicasConfig,
+		database.ReplicasWithConfigurator,
+		migrator.WithConfigurator,
+		repository.NewCacheConfig,
+		repository.NewRepository,
+		expirer.NewConfig,
+		expirer.WithConfigurator,
+		policy.NewConfig,
+		policy.WithConfigurator,
+		reserved.NewConfig,
+		reserved.WithConfigurator,
+		alias.NewConfig,
+		alias.WithConfigurator,
+		metadata.NewConfig,
+		metadata.WithConfigurator,
+		importer.NewConfig,
+		importer.WithConfigurator,
+		exporter.NewExporter,
+		outbox.NewConfig,
+		outbox.WithConfigurator,
+		webhook.NewConfig,
+		webhook.WithConfigurator,
+		changes.NewConfig,
+		changes.WithConfigurator,
 		validator.New,
 	)
 }
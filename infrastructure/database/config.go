@@ -0,0 +1,26 @@
+package database
+
+import "github.com/diez37/go-packages/clients/db"
+
+const (
+	PostgresDriver = "postgres"
+	MemoryDriver   = "memory"
+
+	MySQLDialect    = "mysql"
+	SQLiteDialect   = "sqlite3"
+	PostgresDialect = "postgres"
+)
+
+// Dialect returns name of goqu dialect for db driver
+func Dialect(driver string) string {
+	switch driver {
+	case db.MySQLDriver:
+		return MySQLDialect
+	case db.SQLiteDriver:
+		return SQLiteDialect
+	case PostgresDriver:
+		return PostgresDialect
+	}
+
+	return ""
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/database/postgres"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/clients/db/mysql"
+	"github.com/diez37/go-packages/clients/db/sqlite"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/mysql"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"time"
+)
+
+func WithConfigurator(
+	config *db.Config,
+	configurator configurator.Configurator,
+	informer log.Informer,
+	mysqlConfig *mysql.Config,
+	sqliteConfig *sqlite.Config,
+	postgresConfig *postgres.Config,
+) (*goqu.Database, error) {
+	if driver := configurator.GetString(db.DriverFieldName); driver != "" && config.Driver == "" {
+		config.Driver = driver
+	}
+
+	if config.Driver == "" {
+		return nil, errors.New("db: driver cannot be empty")
+	}
+
+	mysqlConfig = mysql.Configuration(mysqlConfig, configurator)
+	sqliteConfig = sqlite.Configuration(sqliteConfig, configurator)
+	postgresConfig = postgres.Configuration(postgresConfig, configurator)
+
+	return NewDatabase(config, informer, mysqlConfig, sqliteConfig, postgresConfig)
+}
+
+// NewDatabase opening connection for configured driver and wrapping it with goqu dialect of this driver,
+// memory driver does not use any connection and gets nil
+func NewDatabase(
+	config *db.Config,
+	informer log.Informer,
+	mysqlConfig *mysql.Config,
+	sqliteConfig *sqlite.Config,
+	postgresConfig *postgres.Config,
+) (*goqu.Database, error) {
+	var connection goqu.SQLDatabase
+	var err error
+
+	switch config.Driver {
+	case db.MySQLDriver:
+		informer.Info("db: mysql usage")
+
+		connection, err = newMySQL(mysqlConfig, informer)
+	case db.SQLiteDriver:
+		informer.Info("db: sqlite usage")
+
+		connection, err = newSQLite(sqliteConfig, informer)
+	case PostgresDriver:
+		informer.Info("db: postgres usage")
+
+		connection, err = postgres.NewPostgres(postgresConfig, informer)
+	case MemoryDriver:
+		informer.Info("db: memory usage")
+
+		return nil, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("db: driver '%s' unknown", config.Driver))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return goqu.New(Dialect(config.Driver), connection), nil
+}
+
+// newMySQL opening mysql connection, multiStatements is required by migrations
+func newMySQL(config *mysql.Config, informer log.Informer) (*sql.DB, error) {
+	informer.Infof("mysql: host - %s, port - %d", config.Host, config.Port)
+	informer.Infof("mysql: used database - %s", config.DataBase)
+
+	dsn := mysqlDriver.NewConfig()
+	dsn.User = config.User
+	dsn.Passwd = config.Password
+	dsn.Net = "tcp"
+	dsn.Addr = fmt.Sprintf("%s:%d", config.Host, config.Port)
+	dsn.DBName = config.DataBase
+	dsn.MultiStatements = true
+
+	return sql.Open("mysql", mysqlDsn(dsn))
+}
+
+// mysqlDsn returns dsn of config with options which repository relies on: parseTime scans datetime columns
+// into time.Time of UTC, clientFoundRows makes RowsAffected count matched rows like sqlite and postgres do,
+// not only the changed ones
+func mysqlDsn(config *mysqlDriver.Config) string {
+	config.ParseTime = true
+	config.Loc = time.UTC
+	config.ClientFoundRows = true
+
+	if _, ok := config.Params["charset"]; !ok {
+		if config.Params == nil {
+			config.Params = map[string]string{}
+		}
+
+		config.Params["charset"] = "utf8mb4"
+	}
+
+	return config.FormatDSN()
+}
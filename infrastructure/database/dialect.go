@@ -0,0 +1,16 @@
+package database
+
+import (
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/dialect/sqlite3"
+)
+
+// SQLiteTimeFormat fixed width format of times stored by sqlite as text, keeps lexical order equal to chronological
+const SQLiteTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+func init() {
+	options := sqlite3.DialectOptions()
+	options.TimeFormat = SQLiteTimeFormat
+
+	goqu.RegisterDialect(SQLiteDialect, options)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+const (
+	HostFieldName     = "db.postgres.host"
+	PortFieldName     = "db.postgres.port"
+	UserFieldName     = "db.postgres.auth.user"
+	PasswordFieldName = "db.postgres.auth.password"
+	DataBaseFieldName = "db.postgres.name"
+	SSLModeFieldName  = "db.postgres.sslmode"
+
+	HostDefault    string = "127.0.0.1"
+	PortDefault    uint32 = 5432
+	SSLModeDefault string = "disable"
+)
+
+type Config struct {
+	Host string
+	Port uint32
+
+	User     string
+	Password string
+
+	DataBase string
+	SSLMode  string
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
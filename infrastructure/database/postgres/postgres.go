@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	_ "github.com/lib/pq"
+	"net/url"
+)
+
+func WithConfigurator(config *Config, configurator configurator.Configurator, informer log.Informer) (*sql.DB, error) {
+	config = Configuration(config, configurator)
+
+	return NewPostgres(config, informer)
+}
+
+func Configuration(config *Config, configurator configurator.Configurator) *Config {
+	if host := configurator.GetString(HostFieldName); host != "" && config.Host == HostDefault {
+		config.Host = host
+	}
+
+	if port := configurator.GetUint32(PortFieldName); port > 0 && config.Port == PortDefault {
+		config.Port = port
+	}
+
+	if user := configurator.GetString(UserFieldName); user != "" {
+		config.User = user
+	}
+
+	if password := configurator.GetString(PasswordFieldName); password != "" {
+		config.Password = password
+	}
+
+	if dataBase := configurator.GetString(DataBaseFieldName); dataBase != "" {
+		config.DataBase = dataBase
+	}
+
+	if sslMode := configurator.GetString(SSLModeFieldName); sslMode != "" && config.SSLMode == SSLModeDefault {
+		config.SSLMode = sslMode
+	}
+
+	return config
+}
+
+func NewPostgres(config *Config, informer log.Informer) (*sql.DB, error) {
+	informer.Infof("postgres: host - %s, port - %d", config.Host, config.Port)
+	informer.Infof("postgres: used database - %s", config.DataBase)
+
+	dsn := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(config.User, config.Password),
+		Host:     fmt.Sprintf("%s:%d", config.Host, config.Port),
+		Path:     config.DataBase,
+		RawQuery: url.Values{"sslmode": []string{config.SSLMode}}.Encode(),
+	}
+
+	return sql.Open("postgres", dsn.String())
+}
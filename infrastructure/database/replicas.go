@@ -0,0 +1,118 @@
+package database
+
+import (
+	"database/sql"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"github.com/doug-martin/goqu/v9"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"time"
+)
+
+const (
+	ReplicasFieldName                = "db.replicas"
+	ReadYourWritesFieldName          = "db.read_your_writes"
+	ReadYourWritesByAddressFieldName = "db.read_your_writes_by_address"
+
+	ReadYourWritesDefault          = time.Duration(0)
+	ReadYourWritesByAddressDefault = false
+)
+
+type ReplicasConfig struct {
+	// DSNs of read replicas opened by the configured driver
+	DSNs []string
+	// ReadYourWrites period after write while reads of the same session are sent to primary, zero disables pinning
+	ReadYourWrites time.Duration
+	// ReadYourWritesByAddress address of client is session of requests without session header,
+	// clients behind the same proxy or nat share the session
+	ReadYourWritesByAddress bool
+}
+
+func NewReplicasConfig() *ReplicasConfig {
+	return &ReplicasConfig{}
+}
+
+// Replicas connections of read replicas, empty when reads are sent to primary
+type Replicas []goqu.SQLDatabase
+
+// driverNames names of database/sql drivers of db drivers
+var driverNames = map[string]string{
+	db.MySQLDriver:  "mysql",
+	db.SQLiteDriver: "sqlite",
+	PostgresDriver:  "postgres",
+}
+
+func ReplicasWithConfigurator(
+	configurator configurator.Configurator,
+	config *db.Config,
+	replicasConfig *ReplicasConfig,
+	informer log.Informer,
+) (Replicas, error) {
+	configurator.SetDefault(ReadYourWritesFieldName, ReadYourWritesDefault)
+	configurator.SetDefault(ReadYourWritesByAddressFieldName, ReadYourWritesByAddressDefault)
+
+	if dsns := configurator.GetStringSlice(ReplicasFieldName); len(dsns) > 0 && len(replicasConfig.DSNs) == 0 {
+		replicasConfig.DSNs = dsns
+	}
+
+	if readYourWrites := configurator.GetDuration(ReadYourWritesFieldName); readYourWrites > 0 && replicasConfig.ReadYourWrites == ReadYourWritesDefault {
+		replicasConfig.ReadYourWrites = readYourWrites
+	}
+
+	if byAddress := configurator.GetBool(ReadYourWritesByAddressFieldName); byAddress && replicasConfig.ReadYourWritesByAddress == ReadYourWritesByAddressDefault {
+		replicasConfig.ReadYourWritesByAddress = byAddress
+	}
+
+	return NewReplicas(config, replicasConfig, informer)
+}
+
+// NewReplicas opening connections of replicas by driver of primary, memory driver has no replicas
+func NewReplicas(config *db.Config, replicasConfig *ReplicasConfig, informer log.Informer) (Replicas, error) {
+	driverName, ok := driverNames[config.Driver]
+	if !ok {
+		return nil, nil
+	}
+
+	informer.Infof(
+		"db: replicas - %d, read your writes - %s, by address - %t",
+		len(replicasConfig.DSNs),
+		replicasConfig.ReadYourWrites,
+		replicasConfig.ReadYourWritesByAddress,
+	)
+
+	replicas := make(Replicas, 0, len(replicasConfig.DSNs))
+
+	for _, dsn := range replicasConfig.DSNs {
+		dsn, err := replicaDsn(config.Driver, dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		connection, err := sql.Open(driverName, dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		replicas = append(replicas, connection)
+	}
+
+	return replicas, nil
+}
+
+// replicaDsn returns dsn of replica completed by options of dsn of primary of driver
+func replicaDsn(driver string, dsn string) (string, error) {
+	switch driver {
+	case db.MySQLDriver:
+		config, err := mysqlDriver.ParseDSN(dsn)
+		if err != nil {
+			return "", err
+		}
+
+		return mysqlDsn(config), nil
+	case db.SQLiteDriver:
+		return sqliteDsn(dsn), nil
+	}
+
+	return dsn, nil
+}
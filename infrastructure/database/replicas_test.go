@@ -0,0 +1,43 @@
+package database
+
+import (
+	"github.com/diez37/go-packages/clients/db"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"testing"
+	"time"
+)
+
+func TestReplicaDsn_MySQL(t *testing.T) {
+	dsn, err := replicaDsn(db.MySQLDriver, "user:password@tcp(replica:3306)/logins?charset=utf8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := mysqlDriver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !config.ParseTime || config.Loc != time.UTC || !config.ClientFoundRows {
+		t.Fatalf("dsn of replica '%s' lacks options of primary", dsn)
+	}
+
+	if config.Addr != "replica:3306" || config.DBName != "logins" || config.Params["charset"] != "utf8" {
+		t.Fatalf("dsn of replica '%s' lost its own options", dsn)
+	}
+
+	if _, err := replicaDsn(db.MySQLDriver, "replica:3306"); err == nil {
+		t.Fatal("invalid dsn of replica is accepted")
+	}
+}
+
+func TestReplicaDsn_SQLite(t *testing.T) {
+	dsn, err := replicaDsn(db.SQLiteDriver, "./replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dsn != sqliteDsn("./replica") {
+		t.Fatalf("dsn of replica '%s', expected '%s'", dsn, sqliteDsn("./replica"))
+	}
+}
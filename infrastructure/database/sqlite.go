@@ -0,0 +1,55 @@
+package database
+
+import (
+	"fmt"
+	"github.com/diez37/go-packages/clients/db/sqlite"
+	"github.com/diez37/go-packages/log"
+	"github.com/doug-martin/goqu/v9"
+	"net/url"
+	"strings"
+)
+
+const (
+	// sqliteTxLock lock taken by begin of sqlite transaction, immediate takes write lock at once, so transaction
+	// reading before its write doesn't fail to upgrade its read lock shared with another transaction
+	sqliteTxLock = "immediate"
+	// sqliteBusyTimeout milliseconds waited by sqlite for lock of database held by another connection
+	sqliteBusyTimeout = 5000
+)
+
+// sqliteDsn returns dsn with immediate transactions and busy timeout, unless dsn sets them itself
+func sqliteDsn(dsn string) string {
+	var params []string
+
+	query := ""
+	if index := strings.IndexByte(dsn, '?'); index >= 0 {
+		dsn, query = dsn[:index], dsn[index+1:]
+	}
+
+	if query != "" {
+		params = append(params, query)
+	}
+
+	// malformed query is reported by driver on open
+	values, _ := url.ParseQuery(query)
+
+	if values.Get("_txlock") == "" {
+		params = append(params, "_txlock="+sqliteTxLock)
+	}
+
+	busyTimeout := false
+	for _, pragma := range values["_pragma"] {
+		busyTimeout = busyTimeout || strings.HasPrefix(strings.ToLower(strings.TrimSpace(pragma)), "busy_timeout")
+	}
+
+	if !busyTimeout {
+		params = append(params, fmt.Sprintf("_pragma=busy_timeout(%d)", sqliteBusyTimeout))
+	}
+
+	return dsn + "?" + strings.Join(params, "&")
+}
+
+// newSQLite opening sqlite connection of dsn completed by sqliteDsn
+func newSQLite(config *sqlite.Config, informer log.Informer) (goqu.SQLDatabase, error) {
+	return sqlite.NewSQLite(&sqlite.Config{Dsn: sqliteDsn(config.Dsn)}, informer)
+}
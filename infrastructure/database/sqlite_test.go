@@ -0,0 +1,17 @@
+package database
+
+import "testing"
+
+func TestSqliteDsn(t *testing.T) {
+	for dsn, expected := range map[string]string{
+		"./db":                                  "./db?_txlock=immediate&_pragma=busy_timeout(5000)",
+		"file:./db?mode=rwc":                    "file:./db?mode=rwc&_txlock=immediate&_pragma=busy_timeout(5000)",
+		"file:./db?_pragma=busy_timeout(100)":   "file:./db?_pragma=busy_timeout(100)&_txlock=immediate",
+		"file:./db?_txlock=deferred":            "file:./db?_txlock=deferred&_pragma=busy_timeout(5000)",
+		"file:./db?_pragma=foreign_keys(1)&x=1": "file:./db?_pragma=foreign_keys(1)&x=1&_txlock=immediate&_pragma=busy_timeout(5000)",
+	} {
+		if actual := sqliteDsn(dsn); actual != expected {
+			t.Errorf("dsn of '%s' is '%s', expected '%s'", dsn, actual, expected)
+		}
+	}
+}
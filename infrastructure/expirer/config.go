@@ -0,0 +1,18 @@
+package expirer
+
+import "time"
+
+const (
+	IntervalFieldName = "ban.expirer.interval"
+
+	IntervalDefault = time.Minute
+)
+
+type Config struct {
+	// Interval between checks of expired bans
+	Interval time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
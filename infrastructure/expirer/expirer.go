@@ -0,0 +1,82 @@
+package expirer
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	stdTime "time"
+)
+
+// Expirer lifting timed bans after their expiration
+type Expirer struct {
+	config  *Config
+	blocker repository.Blocker
+	tracer  trace.Tracer
+	logger  log.Logger
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Expirer {
+	configurator.SetDefault(IntervalFieldName, IntervalDefault)
+
+	if interval := configurator.GetDuration(IntervalFieldName); interval > 0 && config.Interval == IntervalDefault {
+		config.Interval = interval
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = IntervalDefault
+	}
+
+	return NewExpirer(config, repository, tracer, logger)
+}
+
+func NewExpirer(config *Config, blocker repository.Blocker, tracer trace.Tracer, logger log.Logger) *Expirer {
+	logger.Infof("ban.expirer: interval - %s", config.Interval)
+
+	return &Expirer{config: config, blocker: blocker, tracer: tracer, logger: logger}
+}
+
+// Run checking expired bans every Config.Interval until ctx is done
+func (expirer *Expirer) Run(ctx context.Context) error {
+	ticker := stdTime.NewTicker(expirer.config.Interval)
+	defer ticker.Stop()
+
+	expirer.logger.Info("ban.expirer: started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			expirer.logger.Info("ban.expirer: shutdown")
+
+			return nil
+		case <-ticker.C:
+			expirer.expire(ctx)
+		}
+	}
+}
+
+func (expirer *Expirer) expire(ctx context.Context) {
+	ctx, span := expirer.tracer.Start(ctx, "expire")
+	defer span.End()
+
+	count, err := expirer.blocker.UnbanExpired(ctx, time.NowUTC())
+	if err != nil {
+		expirer.logger.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("count", count))
+
+	if count > 0 {
+		expirer.logger.Infof("ban.expirer: unbanned %d logins", count)
+	}
+}
@@ -0,0 +1,96 @@
+package expirer
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	stdTime "time"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+func TestExpirer_Run(t *testing.T) {
+	memory := repository.NewMemory(testTracer)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	expiring := time.NowUTC().Add(50 * stdTime.Millisecond)
+	future := time.NowUTC().Add(stdTime.Hour)
+
+	logins := map[string]*repository.Login{}
+
+	for _, login := range []*repository.Login{
+		{Login: "expiring", Banned: true, BannedUntil: &expiring, BanReason: "spam"},
+		{Login: "future", Banned: true, BannedUntil: &future, BanReason: "spam"},
+		{Login: "permanent", Banned: true, BanReason: "spam"},
+	} {
+		inserted, err := memory.Insert(ctx, login)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logins[login.Login] = inserted
+	}
+
+	// bans of every tenant are lifted
+	acme, err := memory.Insert(repository.WithTenant(ctx, "acme"), &repository.Login{Login: "expiring", Banned: true, BannedUntil: &expiring})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expirer := NewExpirer(&Config{Interval: 10 * stdTime.Millisecond}, memory, testTracer, testLogger{t})
+
+	done := make(chan error)
+	go func() {
+		done <- expirer.Run(ctx)
+	}()
+
+	banned := func(ctx context.Context, login *repository.Login) bool {
+		found, err := memory.FindByUuid(ctx, login.Uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return found.Banned
+	}
+
+	for deadline := stdTime.Now().Add(5 * stdTime.Second); banned(ctx, logins["expiring"]); {
+		if stdTime.Now().After(deadline) {
+			t.Fatal("expired ban isn't lifted")
+		}
+
+		stdTime.Sleep(10 * stdTime.Millisecond)
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	if banned(repository.WithTenant(context.Background(), "acme"), acme) {
+		t.Fatal("expired ban of another tenant isn't lifted")
+	}
+
+	for _, login := range []string{"future", "permanent"} {
+		if !banned(context.Background(), logins[login]) {
+			t.Fatalf("not expired ban of '%s' is lifted", login)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// encoder writing records in one of formats
+type encoder interface {
+	encode(record *Record) error
+	// flush writing buffered records to underlying writer
+	flush() error
+}
+
+func newEncoder(writer io.Writer, format string) (encoder, error) {
+	switch format {
+	case NDJSONFormat:
+		return &ndjsonEncoder{encoder: json.NewEncoder(writer)}, nil
+	case CSVFormat:
+		encoder := &csvEncoder{writer: csv.NewWriter(writer)}
+
+		return encoder, encoder.writer.Write(csvHeader)
+	}
+
+	return nil, fmt.Errorf("%w: %s", InvalidFormatError, format)
+}
+
+// ndjsonEncoder writing every record as json on its own line, json.Encoder is not buffered
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (encoder *ndjsonEncoder) encode(record *Record) error {
+	return encoder.encoder.Encode(record)
+}
+
+func (encoder *ndjsonEncoder) flush() error {
+	return nil
+}
+
+// csvEncoder writing every record as csv row after header row
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func (encoder *csvEncoder) encode(record *Record) error {
+	return encoder.writer.Write(record.csv())
+}
+
+func (encoder *csvEncoder) flush() error {
+	encoder.writer.Flush()
+
+	return encoder.writer.Error()
+}
@@ -0,0 +1,112 @@
+package exporter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+)
+
+// flushEvery count of records after which written output is flushed
+const flushEvery = 500
+
+var (
+	InvalidFormatError = errors.New("format of export must be ndjson or csv")
+)
+
+// flusher writer sending buffered data to client, like http.Flusher
+type flusher interface {
+	Flush()
+}
+
+// Exporter streaming logins from repository.Exporter in ndjson or csv
+type Exporter struct {
+	repository repository.Repository
+	tracer     trace.Tracer
+	logger     log.Logger
+}
+
+func NewExporter(repository repository.Repository, tracer trace.Tracer, logger log.Logger) *Exporter {
+	return &Exporter{repository: repository, tracer: tracer, logger: logger}
+}
+
+// CheckFormat returns InvalidFormatError if format is unknown
+func CheckFormat(format string) error {
+	if format != NDJSONFormat && format != CSVFormat {
+		return InvalidFormatError
+	}
+
+	return nil
+}
+
+// Export writing every login matched by filter to writer in format, returns count of written logins
+func (exporter *Exporter) Export(ctx context.Context, writer io.Writer, format string, filter *repository.Filter) (int, error) {
+	ctx, span := exporter.tracer.Start(ctx, "Export")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("format", format),
+	)
+
+	if err := CheckFormat(format); err != nil {
+		return 0, err
+	}
+
+	buffered := bufio.NewWriter(writer)
+
+	encoder, err := newEncoder(buffered, format)
+	if err != nil {
+		return 0, err
+	}
+
+	flush := func() error {
+		if err := encoder.flush(); err != nil {
+			return err
+		}
+
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+
+		if flusher, ok := writer.(flusher); ok {
+			flusher.Flush()
+		}
+
+		return nil
+	}
+
+	count := 0
+
+	err = exporter.repository.Export(ctx, filter, func(login *repository.Login) error {
+		if err := encoder.encode(newRecord(login)); err != nil {
+			return err
+		}
+
+		count++
+
+		if count%flushEvery == 0 {
+			return flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err := flush(); err != nil {
+		return count, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("count", count),
+	)
+
+	exporter.logger.Infof("export: %d logins in %s", count, format)
+
+	return count, nil
+}
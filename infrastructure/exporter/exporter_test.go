@@ -0,0 +1,114 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"testing"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+// flushedWriter buffer remembering count of lines written to it at every flush
+type flushedWriter struct {
+	bytes.Buffer
+	flushes []int
+}
+
+func (writer *flushedWriter) Flush() {
+	writer.flushes = append(writer.flushes, strings.Count(writer.String(), "\n"))
+}
+
+// newTestExporter returns exporter of memory repository with count logins
+func newTestExporter(t *testing.T, count int) *Exporter {
+	t.Helper()
+
+	memory := repository.NewMemory(testTracer)
+
+	logins := make([]*repository.Login, count)
+	for index := range logins {
+		logins[index] = &repository.Login{Login: fmt.Sprintf("login%04d", index)}
+	}
+
+	if _, err := memory.InsertBatch(context.Background(), logins); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewExporter(memory, testTracer, testLogger{t})
+}
+
+func TestExporter_ExportStreaming(t *testing.T) {
+	exporter := newTestExporter(t, flushEvery*2+1)
+	writer := &flushedWriter{}
+
+	count, err := exporter.Export(context.Background(), writer, NDJSONFormat, &repository.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != flushEvery*2+1 {
+		t.Fatalf("exported %d, expected %d", count, flushEvery*2+1)
+	}
+
+	// every flushEvery records are sent to client before the rest are read
+	if fmt.Sprint(writer.flushes) != fmt.Sprint([]int{flushEvery, flushEvery * 2, flushEvery*2 + 1}) {
+		t.Fatalf("lines written at flushes %v", writer.flushes)
+	}
+
+	decoder := json.NewDecoder(&writer.Buffer)
+
+	for index := 0; decoder.More(); index++ {
+		record := &Record{}
+		if err := decoder.Decode(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := fmt.Sprintf("login%04d", index); record.Login != expected {
+			t.Fatalf("record %d '%s', expected '%s'", index, record.Login, expected)
+		}
+	}
+}
+
+func TestExporter_ExportCSV(t *testing.T) {
+	exporter := newTestExporter(t, 2)
+	writer := &flushedWriter{}
+
+	if _, err := exporter.Export(context.Background(), writer, CSVFormat, &repository.Filter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&writer.Buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 3 || fmt.Sprint(rows[0]) != fmt.Sprint(csvHeader) || rows[1][1] != "login0000" {
+		t.Fatalf("csv rows %v, expected header and 2 logins", rows)
+	}
+}
+
+func TestExporter_ExportFormat(t *testing.T) {
+	if _, err := newTestExporter(t, 1).Export(context.Background(), &flushedWriter{}, "xml", &repository.Filter{}); err != InvalidFormatError {
+		t.Fatalf("export in xml: %v, expected InvalidFormatError", err)
+	}
+}
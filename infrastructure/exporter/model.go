@@ -0,0 +1,61 @@
+package exporter
+
+import (
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+// formats of export
+const (
+	NDJSONFormat = "ndjson"
+	CSVFormat    = "csv"
+)
+
+// Record exported login, has the same fields as login of api
+type Record struct {
+	Uuid        uuid.UUID  `json:"uuid"`
+	Login       string     `json:"login"`
+	Banned      bool       `json:"banned"`
+	BannedUntil *time.Time `json:"bannedUntil"`
+	BanReason   string     `json:"banReason"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdateAt    *time.Time `json:"updateAt"`
+}
+
+// csvHeader names of csv columns in order of Record.csv
+var csvHeader = []string{"uuid", "login", "banned", "bannedUntil", "banReason", "createdAt", "updateAt"}
+
+func newRecord(login *repository.Login) *Record {
+	return &Record{
+		Uuid:        login.Uuid,
+		Login:       login.Login,
+		Banned:      login.Banned,
+		BannedUntil: login.BannedUntil,
+		BanReason:   login.BanReason,
+		CreatedAt:   login.CreatedAt,
+		UpdateAt:    login.UpdateAt,
+	}
+}
+
+// csv returns values of record in order of csvHeader, empty times are empty strings
+func (record *Record) csv() []string {
+	return []string{
+		record.Uuid.String(),
+		record.Login,
+		strconv.FormatBool(record.Banned),
+		formatTime(record.BannedUntil),
+		record.BanReason,
+		formatTime(record.CreatedAt),
+		formatTime(record.UpdateAt),
+	}
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,20 @@
+package importer
+
+const (
+	ChunkSizeFieldName    = "import.chunk_size"
+	MaxBatchRowsFieldName = "import.max_batch_rows"
+
+	ChunkSizeDefault    = uint(1000)
+	MaxBatchRowsDefault = uint(10000)
+)
+
+type Config struct {
+	// ChunkSize count of logins inserted in one transaction
+	ChunkSize uint
+	// MaxBatchRows max count of rows of batch, results of the whole batch are kept in memory until it is inserted
+	MaxBatchRows uint
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+var (
+	InvalidInputError = errors.New("input must be json array or newline delimited json of logins")
+	TooManyRowsError  = errors.New("too many rows")
+)
+
+// decoder streaming rows from json array or newline delimited json
+type decoder struct {
+	json  *json.Decoder
+	array bool
+	index int
+}
+
+func newDecoder(reader io.Reader) (*decoder, error) {
+	buffered := bufio.NewReader(reader)
+
+	for {
+		char, _, err := buffered.ReadRune()
+		if err == io.EOF {
+			return &decoder{json: json.NewDecoder(buffered)}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if unicode.IsSpace(char) {
+			continue
+		}
+
+		if err := buffered.UnreadRune(); err != nil {
+			return nil, err
+		}
+
+		decoder := &decoder{json: json.NewDecoder(buffered), array: char == '['}
+
+		if decoder.array {
+			if _, err := decoder.json.Token(); err != nil {
+				return nil, fmt.Errorf("%w: %s", InvalidInputError, err)
+			}
+		}
+
+		return decoder, nil
+	}
+}
+
+// next decoding the next row, io.EOF at the end of input
+func (decoder *decoder) next() (*Row, error) {
+	if decoder.array && !decoder.json.More() {
+		if _, err := decoder.json.Token(); err != nil {
+			return nil, fmt.Errorf("%w: %s", InvalidInputError, err)
+		}
+
+		return nil, io.EOF
+	}
+
+	row := &Row{}
+	if err := decoder.json.Decode(row); err != nil {
+		if err == io.EOF && !decoder.array {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("%w: row %d: %s", InvalidInputError, decoder.index, err)
+	}
+
+	decoder.index++
+
+	return row, nil
+}
@@ -0,0 +1,335 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/policy"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// loginField name of login field of Row in violations
+const loginField = "login"
+
+// errHeld rolling back transaction of chunk with logins held by cooldown, the chunk is inserted again without them
+var errHeld = errors.New("logins are held by cooldown")
+
+// Importer inserting logins from json array or newline delimited json by chunks
+type Importer struct {
+	config     *Config
+	repository repository.Repository
+	policy     *policy.LoginPolicy
+	cooldown   *alias.Cooldown
+	tracer     trace.Tracer
+	logger     log.Logger
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	policy *policy.LoginPolicy,
+	cooldown *alias.Cooldown,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Importer {
+	configurator.SetDefault(ChunkSizeFieldName, ChunkSizeDefault)
+	configurator.SetDefault(MaxBatchRowsFieldName, MaxBatchRowsDefault)
+
+	if chunkSize := configurator.GetUint(ChunkSizeFieldName); chunkSize > 0 && config.ChunkSize == ChunkSizeDefault {
+		config.ChunkSize = chunkSize
+	}
+
+	if maxBatchRows := configurator.GetUint(MaxBatchRowsFieldName); maxBatchRows > 0 && config.MaxBatchRows == MaxBatchRowsDefault {
+		config.MaxBatchRows = maxBatchRows
+	}
+
+	if config.ChunkSize == 0 {
+		config.ChunkSize = ChunkSizeDefault
+	}
+
+	if config.MaxBatchRows == 0 {
+		config.MaxBatchRows = MaxBatchRowsDefault
+	}
+
+	return NewImporter(config, repository, policy, cooldown, tracer, logger)
+}
+
+func NewImporter(
+	config *Config,
+	repository repository.Repository,
+	policy *policy.LoginPolicy,
+	cooldown *alias.Cooldown,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Importer {
+	return &Importer{
+		config:     config,
+		repository: repository,
+		policy:     policy,
+		cooldown:   cooldown,
+		tracer:     tracer,
+		logger:     logger,
+	}
+}
+
+// Import inserting every row of reader, each chunk of Config.ChunkSize rows in its own transaction,
+// results of every chunk are passed to write in order of rows before reading of the next chunk
+func (importer *Importer) Import(ctx context.Context, reader io.Reader, write func(results []*Result) error) (*Summary, error) {
+	ctx, span := importer.tracer.Start(ctx, "Import")
+	defer span.End()
+
+	return importer.read(ctx, span, reader, 0, write)
+}
+
+// Batch the same as Import, but reader is limited to Config.MaxBatchRows rows for callers keeping every result
+// in memory, TooManyRowsError is returned on the first row after the limit, so rows of the chunk of the row
+// and every next row aren't inserted
+func (importer *Importer) Batch(ctx context.Context, reader io.Reader, write func(results []*Result) error) (*Summary, error) {
+	ctx, span := importer.tracer.Start(ctx, "Batch")
+	defer span.End()
+
+	return importer.read(ctx, span, reader, importer.config.MaxBatchRows, write)
+}
+
+// read inserting rows of reader by chunks, limit is max count of rows, 0 is unlimited
+func (importer *Importer) read(
+	ctx context.Context,
+	span trace.Span,
+	reader io.Reader,
+	limit uint,
+	write func(results []*Result) error,
+) (*Summary, error) {
+	decoder, err := newDecoder(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := importer.repository.ListReserved(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{}
+	rows := make([]*Row, 0, importer.config.ChunkSize)
+
+	for index := 0; ; {
+		row, err := decoder.next()
+		if err != nil && err != io.EOF {
+			return summary, err
+		}
+
+		if row != nil {
+			if limit > 0 && uint(index+len(rows)) >= limit {
+				return summary, fmt.Errorf("%w: max %d", TooManyRowsError, limit)
+			}
+
+			rows = append(rows, row)
+		}
+
+		if len(rows) > 0 && (uint(len(rows)) == importer.config.ChunkSize || err == io.EOF) {
+			results, err := importer.chunk(ctx, index, rows, reservations)
+			if err != nil {
+				return summary, err
+			}
+
+			for _, result := range results {
+				switch result.Status {
+				case CreatedStatus:
+					summary.Created++
+				case DuplicateStatus:
+					summary.Duplicate++
+				case InvalidStatus:
+					summary.Invalid++
+				}
+			}
+
+			if err := write(results); err != nil {
+				return summary, err
+			}
+
+			index += len(rows)
+			rows = rows[:0]
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("created", summary.Created),
+		attribute.Int("duplicate", summary.Duplicate),
+		attribute.Int("invalid", summary.Invalid),
+	)
+
+	importer.logger.Infof("import: created %d, duplicate %d, invalid %d", summary.Created, summary.Duplicate, summary.Invalid)
+
+	return summary, nil
+}
+
+// chunk validating and inserting rows starting from offset in one transaction
+func (importer *Importer) chunk(ctx context.Context, offset int, rows []*Row, reservations []*repository.Reserved) ([]*Result, error) {
+	ctx, span := importer.tracer.Start(ctx, "chunk")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("offset", offset),
+		attribute.Int("count", len(rows)),
+	)
+
+	results := make([]*Result, len(rows))
+
+	var logins []*repository.Login
+	var inserted []*Result
+
+	for index, row := range rows {
+		results[index] = &Result{Index: offset + index, Login: row.Login}
+
+		if violations := importer.validate(row, reservations); len(violations) > 0 {
+			results[index].Status = InvalidStatus
+			results[index].Violations = violations
+			continue
+		}
+
+		login := &repository.Login{Login: row.Login}
+		if row.Banned != nil && *row.Banned {
+			login.Banned = true
+			login.BannedUntil = row.BannedUntil
+			login.BanReason = row.BanReason
+		}
+
+		logins = append(logins, login)
+		inserted = append(inserted, results[index])
+	}
+
+	for retried := false; len(logins) > 0; {
+		err := importer.repository.Transaction(ctx, func(tx repository.Repository) error {
+			return importer.insert(ctx, tx, logins, inserted)
+		})
+
+		if errors.Is(err, errHeld) {
+			logins, inserted = unheld(logins, inserted)
+			continue
+		}
+
+		if errors.Is(err, repository.ErrDuplicateLogin) && !retried {
+			// login was taken concurrently after the check of the batch, the retry checks it again
+			retried = true
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		break
+	}
+
+	return results, nil
+}
+
+// insert inserting logins by tx and setting their results, errHeld is returned if any inserted login is held
+// by cooldown, results of the held logins are set invalid
+func (importer *Importer) insert(ctx context.Context, tx repository.Repository, logins []*repository.Login, results []*Result) error {
+	errs, err := tx.InsertBatch(ctx, logins)
+	if err != nil {
+		return err
+	}
+
+	// checked after the insert, which waits for a concurrent rename holding the login, so alias of the rename is seen
+	cooldown := importer.cooldown.WithAliases(tx)
+	held := false
+
+	for index, login := range logins {
+		if errs[index] != nil {
+			results[index].Status = DuplicateStatus
+			continue
+		}
+
+		alias, releasedAt, err := cooldown.Held(ctx, login.Login, uuid.Nil)
+		if err != nil {
+			return err
+		}
+
+		if alias != nil {
+			held = true
+			results[index].Status = InvalidStatus
+			results[index].Violations = []*Violation{{
+				Field:   loginField,
+				Rule:    CooldownRule,
+				Message: fmt.Sprintf("login was renamed and is released at %s", releasedAt.Format(time.RFC3339)),
+			}}
+
+			continue
+		}
+
+		loginUuid := login.Uuid
+		results[index].Status = CreatedStatus
+		results[index].Uuid = &loginUuid
+	}
+
+	if held {
+		return errHeld
+	}
+
+	return nil
+}
+
+// unheld returns logins with their results excluding logins held by cooldown
+func unheld(logins []*repository.Login, results []*Result) ([]*repository.Login, []*Result) {
+	var restLogins []*repository.Login
+	var restResults []*Result
+
+	for index, result := range results {
+		if result.Status == InvalidStatus {
+			continue
+		}
+
+		restLogins = append(restLogins, logins[index])
+		restResults = append(restResults, result)
+	}
+
+	return restLogins, restResults
+}
+
+// validate returns violations of login policy, reservations and sizes of columns by row
+func (importer *Importer) validate(row *Row, reservations []*repository.Reserved) []*Violation {
+	var violations []*Violation
+
+	for _, violation := range importer.policy.Check(row.Login) {
+		violations = append(violations, &Violation{Field: loginField, Rule: violation.Rule, Message: violation.Message})
+	}
+
+	canonical := repository.Canonical(row.Login)
+	for _, reserved := range reservations {
+		if reserved.Match(canonical) {
+			violations = append(violations, &Violation{
+				Field:   loginField,
+				Rule:    ReservedRule,
+				Message: fmt.Sprintf("login is reserved by '%s'", reserved.Pattern),
+			})
+
+			break
+		}
+	}
+
+	if utf8.RuneCountInString(row.BanReason) > BanReasonMaxLength {
+		violations = append(violations, &Violation{
+			Field:   "banReason",
+			Rule:    MaxLengthRule,
+			Message: fmt.Sprintf("must be at most %d characters long", BanReasonMaxLength),
+		})
+	}
+
+	return violations
+}
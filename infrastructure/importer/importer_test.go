@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/policy"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/clients/db"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+// testRows newline delimited json of rows a, b, c and d
+const testRows = `{"login":"a"}
+{"login":"b"}
+{"login":"c"}
+{"login":"d"}
+`
+
+// newTestImporter returns importer of memory repository inserting chunks of 2 rows and batches up to 3 rows,
+// former logins of renamed logins are held by cooldown for an hour
+func newTestImporter(t *testing.T) (*Importer, repository.Repository) {
+	t.Helper()
+
+	loginPolicy, err := policy.NewLoginPolicy(&policy.Config{
+		MinLength: policy.MinLengthDefault,
+		MaxLength: policy.MaxLengthDefault,
+		Classes:   policy.ClassesDefault,
+	}, testLogger{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memory := repository.NewMemory(testTracer)
+
+	cooldown := alias.NewCooldown(&alias.Config{Cooldown: time.Hour}, memory, testTracer, testLogger{t})
+
+	return NewImporter(&Config{ChunkSize: 2, MaxBatchRows: 3}, memory, loginPolicy, cooldown, testTracer, testLogger{t}), memory
+}
+
+func TestImporter_BatchTooManyRows(t *testing.T) {
+	importer, memory := newTestImporter(t)
+	ctx := context.Background()
+
+	var results []*Result
+
+	_, err := importer.Batch(ctx, strings.NewReader(testRows), func(chunk []*Result) error {
+		results = append(results, chunk...)
+		return nil
+	})
+	if !errors.Is(err, TooManyRowsError) {
+		t.Fatalf("batch of 4 rows: %v, expected TooManyRowsError", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("results %d, expected 2 of the first chunk", len(results))
+	}
+
+	for _, login := range []string{"c", "d"} {
+		if _, err := memory.FindByLogin(ctx, login); err != db.RecordNotFoundError {
+			t.Fatalf("find of login '%s' after the limit: %v, expected db.RecordNotFoundError", login, err)
+		}
+	}
+}
+
+func TestImporter_Batch(t *testing.T) {
+	importer, _ := newTestImporter(t)
+
+	summary, err := importer.Batch(context.Background(), strings.NewReader(`[{"login":"a"},{"login":"b"},{"login":"c"}]`), func([]*Result) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Created != 3 {
+		t.Fatalf("created %d, expected 3", summary.Created)
+	}
+}
+
+func TestImporter_Import(t *testing.T) {
+	importer, _ := newTestImporter(t)
+
+	summary, err := importer.Import(context.Background(), strings.NewReader(testRows), func([]*Result) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Created != 4 {
+		t.Fatalf("created by unlimited import %d, expected 4", summary.Created)
+	}
+}
+
+func TestImporter_Cooldown(t *testing.T) {
+	importer, memory := newTestImporter(t)
+	ctx := context.Background()
+
+	login, err := memory.Insert(ctx, &repository.Login{Login: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login.Login = "renamed"
+	if _, err := memory.Update(ctx, login); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []*Result
+
+	summary, err := importer.Import(ctx, strings.NewReader(testRows), func(chunk []*Result) error {
+		results = append(results, chunk...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Created != 3 || summary.Invalid != 1 {
+		t.Fatalf("created %d and invalid %d, expected 3 and 1", summary.Created, summary.Invalid)
+	}
+
+	if results[0].Status != InvalidStatus || len(results[0].Violations) != 1 || results[0].Violations[0].Rule != CooldownRule {
+		t.Fatalf("result of held login '%s' %+v, expected violation of cooldown", results[0].Login, results[0])
+	}
+
+	if results[1].Status != CreatedStatus || results[1].Uuid == nil {
+		t.Fatalf("result of login '%s' of the chunk of held login %+v, expected created", results[1].Login, results[1])
+	}
+
+	if _, err := memory.FindByLogin(ctx, "a"); err != db.RecordNotFoundError {
+		t.Fatalf("find of held login: %v, expected db.RecordNotFoundError", err)
+	}
+
+	if _, err := memory.FindByLogin(ctx, "b"); err != nil {
+		t.Fatalf("find of login of the chunk of held login: %v", err)
+	}
+}
+
+func TestImporter_Reserved(t *testing.T) {
+	importer, memory := newTestImporter(t)
+	ctx := context.Background()
+
+	reserved, err := repository.NewReserved("b*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := memory.AddReserved(ctx, reserved); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []*Result
+
+	summary, err := importer.Import(ctx, strings.NewReader(testRows), func(chunk []*Result) error {
+		results = append(results, chunk...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Created != 3 || summary.Invalid != 1 {
+		t.Fatalf("created %d and invalid %d, expected 3 and 1", summary.Created, summary.Invalid)
+	}
+
+	if results[1].Status != InvalidStatus || len(results[1].Violations) != 1 || results[1].Violations[0].Rule != ReservedRule {
+		t.Fatalf("result of reserved login '%s' %+v, expected violation of reservation", results[1].Login, results[1])
+	}
+}
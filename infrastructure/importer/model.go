@@ -0,0 +1,54 @@
+package importer
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// statuses of Result
+const (
+	CreatedStatus   = "created"
+	DuplicateStatus = "duplicate"
+	InvalidStatus   = "invalid"
+)
+
+// rules of Violation checked by Importer in addition to policy.LoginPolicy
+const (
+	ReservedRule  = "reserved"
+	CooldownRule  = "cooldown"
+	MaxLengthRule = "max_length"
+)
+
+// BanReasonMaxLength size of ban_reason column
+const BanReasonMaxLength = 64
+
+// Row imported login, has the same fields as login of api
+type Row struct {
+	Login       string     `json:"login"`
+	Banned      *bool      `json:"banned"`
+	BannedUntil *time.Time `json:"bannedUntil"`
+	BanReason   string     `json:"banReason"`
+}
+
+// Result of import of Row with index of the row in input
+type Result struct {
+	Index      int          `json:"index"`
+	Login      string       `json:"login"`
+	Status     string       `json:"status"`
+	Uuid       *uuid.UUID   `json:"uuid,omitempty"`
+	Violations []*Violation `json:"violations,omitempty"`
+}
+
+// Violation of validation rule by field of Row
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Summary counts of import results by status
+type Summary struct {
+	Created   int `json:"created"`
+	Duplicate int `json:"duplicate"`
+	Invalid   int `json:"invalid"`
+}
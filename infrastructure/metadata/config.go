@@ -0,0 +1,16 @@
+package metadata
+
+const (
+	MaxSizeFieldName = "login.metadata.max_size"
+
+	MaxSizeDefault = 4096
+)
+
+type Config struct {
+	// MaxSize max size of metadata of login in bytes of its compact json
+	MaxSize int
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
@@ -0,0 +1,148 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+)
+
+var (
+	// InvalidMetadataError returned when metadata or its merge patch isn't json object
+	InvalidMetadataError = errors.New("metadata must be json object")
+	// TooLargeMetadataError returned when metadata is larger than Config.MaxSize
+	TooLargeMetadataError = errors.New("metadata is too large")
+)
+
+// Limiter normalizing metadata of logins to compact json objects not larger than Config.MaxSize
+type Limiter struct {
+	config *Config
+}
+
+func WithConfigurator(configurator configurator.Configurator, config *Config, logger log.Logger) *Limiter {
+	configurator.SetDefault(MaxSizeFieldName, MaxSizeDefault)
+
+	if maxSize := configurator.GetInt(MaxSizeFieldName); maxSize > 0 && config.MaxSize == MaxSizeDefault {
+		config.MaxSize = maxSize
+	}
+
+	if config.MaxSize <= 0 {
+		config.MaxSize = MaxSizeDefault
+	}
+
+	return NewLimiter(config, logger)
+}
+
+func NewLimiter(config *Config, logger log.Logger) *Limiter {
+	logger.Infof("login.metadata: max size - %d", config.MaxSize)
+
+	return &Limiter{config: config}
+}
+
+// Normalize returns compact json of metadata object, repository.EmptyMetadata for missing or null metadata
+func (limiter *Limiter) Normalize(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return repository.EmptyMetadata, nil
+	}
+
+	value, err := decode(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return limiter.encode(value)
+}
+
+// Merge returns metadata with applied json merge patch (RFC 7396), null patch removes every attribute
+func (limiter *Limiter) Merge(metadata string, patch json.RawMessage) (string, error) {
+	if len(patch) == 0 {
+		return metadata, nil
+	}
+
+	target, err := decode([]byte(metadata))
+	if err != nil {
+		return "", err
+	}
+
+	value, err := decode(patch)
+	if err != nil {
+		return "", err
+	}
+
+	return limiter.encode(merge(target, value))
+}
+
+// encode returns compact json of metadata object, TooLargeMetadataError if it's larger than Config.MaxSize
+func (limiter *Limiter) encode(value interface{}) (string, error) {
+	if value == nil {
+		return repository.EmptyMetadata, nil
+	}
+
+	if _, ok := value.(map[string]interface{}); !ok {
+		return "", InvalidMetadataError
+	}
+
+	buffer := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	metadata := bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))
+	if len(metadata) > limiter.config.MaxSize {
+		return "", fmt.Errorf("%w: %d bytes, max %d", TooLargeMetadataError, len(metadata), limiter.config.MaxSize)
+	}
+
+	return string(metadata), nil
+}
+
+// decode returns json object or null of raw, numbers are kept as json.Number to not lose their precision
+func decode(raw []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %s", InvalidMetadataError, err)
+	}
+
+	if decoder.More() {
+		return nil, InvalidMetadataError
+	}
+
+	if _, ok := value.(map[string]interface{}); !ok && value != nil {
+		return nil, InvalidMetadataError
+	}
+
+	return value, nil
+}
+
+// merge applying merge patch to target by RFC 7396
+func merge(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = merge(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"strings"
+	"testing"
+)
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+func TestLimiter_Normalize(t *testing.T) {
+	limiter := NewLimiter(&Config{MaxSize: MaxSizeDefault}, testLogger{t})
+
+	for raw, expected := range map[string]string{
+		"":                                 repository.EmptyMetadata,
+		"null":                             repository.EmptyMetadata,
+		" { \"a\" : 1 } ":                  `{"a":1}`,
+		`{"id":12345678901234567890}`:      `{"id":12345678901234567890}`,
+		`{"url":"https://a.b/?c=d&e=<f>"}`: `{"url":"https://a.b/?c=d&e=<f>"}`,
+	} {
+		actual, err := limiter.Normalize(json.RawMessage(raw))
+		if err != nil {
+			t.Errorf("normalize of '%s': %v", raw, err)
+			continue
+		}
+
+		if actual != expected {
+			t.Errorf("normalized '%s': '%s', expected '%s'", raw, actual, expected)
+		}
+	}
+
+	for _, raw := range []string{"[]", `"a"`, "1", "{", `{"a":1} {"b":2}`} {
+		if _, err := limiter.Normalize(json.RawMessage(raw)); !errors.Is(err, InvalidMetadataError) {
+			t.Errorf("normalize of '%s': %v, expected InvalidMetadataError", raw, err)
+		}
+	}
+}
+
+func TestLimiter_Merge(t *testing.T) {
+	limiter := NewLimiter(&Config{MaxSize: MaxSizeDefault}, testLogger{t})
+
+	// examples of RFC 7396
+	for _, test := range []struct {
+		metadata string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`{"a":"foo"}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"a":"b"}`, ``, `{"a":"b"}`},
+		{`{"a":"b"}`, `null`, repository.EmptyMetadata},
+	} {
+		actual, err := limiter.Merge(test.metadata, json.RawMessage(test.patch))
+		if err != nil {
+			t.Errorf("merge of '%s' into '%s': %v", test.patch, test.metadata, err)
+			continue
+		}
+
+		if actual != test.expected {
+			t.Errorf("merge of '%s' into '%s': '%s', expected '%s'", test.patch, test.metadata, actual, test.expected)
+		}
+	}
+
+	for _, patch := range []string{`["a"]`, `"a"`, "{"} {
+		if _, err := limiter.Merge(`{"a":"b"}`, json.RawMessage(patch)); !errors.Is(err, InvalidMetadataError) {
+			t.Errorf("merge of '%s': %v, expected InvalidMetadataError", patch, err)
+		}
+	}
+}
+
+func TestLimiter_MaxSize(t *testing.T) {
+	limiter := NewLimiter(&Config{MaxSize: 16}, testLogger{t})
+
+	// {"a":"..."} is 8 bytes besides the value
+	fitting := `{"a":"` + strings.Repeat("x", 8) + `"}`
+
+	if _, err := limiter.Normalize(json.RawMessage(fitting)); err != nil {
+		t.Fatalf("normalize of metadata of max size: %v", err)
+	}
+
+	// size is measured by compact json
+	if _, err := limiter.Normalize(json.RawMessage(" {\n  \"a\": \"xxxxxxxx\"\n} ")); err != nil {
+		t.Fatalf("normalize of indented metadata of max size: %v", err)
+	}
+
+	if _, err := limiter.Normalize(json.RawMessage(`{"a":"` + strings.Repeat("x", 9) + `"}`)); !errors.Is(err, TooLargeMetadataError) {
+		t.Fatalf("normalize of metadata over max size: %v, expected TooLargeMetadataError", err)
+	}
+
+	// merged metadata is limited, not the patch
+	if _, err := limiter.Merge(fitting, json.RawMessage(`{"b":1}`)); !errors.Is(err, TooLargeMetadataError) {
+		t.Fatalf("merge over max size: %v, expected TooLargeMetadataError", err)
+	}
+
+	if actual, err := limiter.Merge(fitting, json.RawMessage(`{"a":null,"b":"`+strings.Repeat("y", 8)+`"}`)); err != nil || actual != `{"b":"yyyyyyyy"}` {
+		t.Fatalf("merge replacing attribute of max size: '%s', %v", actual, err)
+	}
+}
@@ -0,0 +1,93 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/doug-martin/goqu/v9"
+	"strings"
+)
+
+// canonicalVersion version of migration adding column of canonical form of login, filled by canonicalize
+const canonicalVersion = 20261017120000
+
+// DuplicateCanonicalError returned when logins have the same canonical form and can't keep unique index on it,
+// conflicting logins must be renamed or deleted by hand, the step is run again by the next start
+var DuplicateCanonicalError = errors.New("logins have the same canonical form")
+
+// canonicalize filling canonical form of every login by repository.Canonical, so lookups find logins stored
+// before the form existed, logins with the same form are reported and nothing is changed
+func canonicalize(ctx context.Context, database *goqu.Database) error {
+	return database.WithTx(func(tx *goqu.TxDatabase) error {
+		sql, args, err := tx.From("logins").
+			Select("id", "login", "login_canonical").
+			Order(goqu.I("id").Asc()).
+			ToSQL()
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		defer rows.Close()
+
+		changed := map[int64]string{}
+		logins := map[string][]string{}
+		var order []string
+
+		for rows.Next() {
+			var id int64
+			var login, stored string
+
+			if err := rows.Scan(&id, &login, &stored); err != nil {
+				return err
+			}
+
+			canonical := repository.Canonical(login)
+			if canonical != stored {
+				changed[id] = canonical
+			}
+
+			if _, ok := logins[canonical]; !ok {
+				order = append(order, canonical)
+			}
+
+			logins[canonical] = append(logins[canonical], fmt.Sprintf("'%s' (id %d)", login, id))
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		var duplicates []string
+		for _, canonical := range order {
+			if len(logins[canonical]) > 1 {
+				duplicates = append(duplicates, fmt.Sprintf("'%s': %s", canonical, strings.Join(logins[canonical], ", ")))
+			}
+		}
+
+		if len(duplicates) > 0 {
+			return fmt.Errorf("%w: %s", DuplicateCanonicalError, strings.Join(duplicates, "; "))
+		}
+
+		for id, canonical := range changed {
+			sql, args, err := tx.Update("logins").
+				Set(goqu.Record{"login_canonical": canonical}).
+				Where(goqu.Ex{"id": id}).
+				ToSQL()
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, sql, args...); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
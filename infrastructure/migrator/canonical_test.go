@@ -0,0 +1,127 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/Diez37/logins/infrastructure/database/postgres"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/clients/db/mysql"
+	"github.com/diez37/go-packages/clients/db/sqlite"
+	"github.com/diez37/go-packages/migrator"
+	"github.com/doug-martin/goqu/v9"
+	"path/filepath"
+	"testing"
+)
+
+// testPreviousVersion version of migration before canonicalVersion
+const testPreviousVersion = 20261017110000
+
+// testInformer writing info of database to log of test
+type testInformer struct {
+	t *testing.T
+}
+
+func (informer testInformer) Infof(format string, args ...interface{}) {
+	informer.t.Logf(format, args...)
+}
+
+func (informer testInformer) Info(args ...interface{}) {
+	informer.t.Log(args...)
+}
+
+// newTestMigrator returns migrator of sqlite database in temporary directory of test migrated up to testPreviousVersion
+// with logins inserted
+func newTestMigrator(t *testing.T, logins ...string) (*sqlMigrator, *goqu.Database) {
+	t.Helper()
+
+	config := &db.Config{Driver: db.SQLiteDriver}
+
+	sqlDatabase, err := database.NewDatabase(
+		config,
+		testInformer{t},
+		&mysql.Config{},
+		&sqlite.Config{Dsn: fmt.Sprintf("file:%s", filepath.Join(t.TempDir(), "db"))},
+		&postgres.Config{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance, err := NewMigrator(&migrator.Config{Source: "file://../../migrations"}, config, sqlDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlMigrator := instance.(*sqlMigrator)
+
+	t.Cleanup(func() {
+		sqlMigrator.migrate.Close()
+	})
+
+	if err := sqlMigrator.migrate.Migrate(testPreviousVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	for index, login := range logins {
+		_, err := sqlDatabase.Insert("logins").
+			Rows(goqu.Record{"uuid": fmt.Sprintf("00000000-0000-0000-0000-%012d", index), "login": login}).
+			Executor().Exec()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return sqlMigrator, sqlDatabase
+}
+
+func TestMigrator_Canonical(t *testing.T) {
+	logins := []string{"Alice", "Straße", "ÉLODIE"}
+
+	migrator, sqlDatabase := newTestMigrator(t, logins...)
+
+	if err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, login := range logins {
+		var canonical string
+
+		found, err := sqlDatabase.From("logins").Select("login_canonical").Where(goqu.Ex{"login": login}).ScanVal(&canonical)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !found || canonical != repository.Canonical(login) {
+			t.Errorf("canonical form of '%s' is '%s', expected '%s'", login, canonical, repository.Canonical(login))
+		}
+	}
+}
+
+func TestMigrator_CanonicalDuplicate(t *testing.T) {
+	migrator, sqlDatabase := newTestMigrator(t, "Alice", "bob", "alice")
+
+	err := migrator.Up()
+	if !errors.Is(err, DuplicateCanonicalError) {
+		t.Fatalf("up: %v, expected DuplicateCanonicalError", err)
+	}
+
+	version, dirty, err := migrator.migrate.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version != canonicalVersion || dirty {
+		t.Fatalf("version %d, dirty %t, expected clean version %d", version, dirty, canonicalVersion)
+	}
+
+	// conflict resolved by hand is migrated by the next up
+	if _, err := sqlDatabase.Delete("logins").Where(goqu.Ex{"login": "alice"}).Executor().Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+}
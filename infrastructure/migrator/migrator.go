@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/migrator"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/golang-migrate/migrate/v4"
+	migrateDatabase "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"strings"
+)
+
+// Migrator applying migrations of the configured db driver
+type Migrator interface {
+	Up() error
+}
+
+func WithConfigurator(configurator configurator.Configurator, config *migrator.Config, dbConfig *db.Config, database *goqu.Database) (Migrator, error) {
+	configurator.SetDefault(migrator.SourceFieldName, migrator.SourceDefault)
+	if directory := configurator.GetString(migrator.SourceFieldName); directory != "" && config.Source == "" {
+		config.Source = directory
+	}
+
+	return NewMigrator(config, dbConfig, database)
+}
+
+// NewMigrator creating migrate.Migrate for migrations from '<source>/<driver>' directory
+func NewMigrator(config *migrator.Config, dbConfig *db.Config, sqlDatabase *goqu.Database) (Migrator, error) {
+	if dbConfig.Driver == database.MemoryDriver {
+		return &memory{}, nil
+	}
+
+	dbInstance, ok := sqlDatabase.Db.(*sql.DB)
+	if !ok {
+		return nil, errors.New("migrator: db instance unknown")
+	}
+
+	var driver migrateDatabase.Driver
+	var err error
+
+	switch dbConfig.Driver {
+	case db.MySQLDriver:
+		driver, err = mysql.WithInstance(dbInstance, &mysql.Config{})
+	case db.SQLiteDriver:
+		driver, err = sqlite.WithInstance(dbInstance, &sqlite.Config{})
+	case database.PostgresDriver:
+		driver, err = postgres.WithInstance(dbInstance, &postgres.Config{})
+	default:
+		return nil, errors.New(fmt.Sprintf("migrator: driver '%s' unknown", dbConfig.Driver))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := migrate.NewWithDatabaseInstance(
+		fmt.Sprintf("%s/%s", strings.TrimRight(config.Source, "/"), dbConfig.Driver),
+		dbConfig.Driver,
+		driver,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlMigrator{migrate: instance, database: sqlDatabase, steps: steps}, nil
+}
+
+// step changing data by go code, when data can't be migrated by sql, after migrations up to version are applied
+// and before the later ones, steps must be repeatable: failed step is run again by the next Up
+type step struct {
+	version uint
+	name    string
+	run     func(ctx context.Context, database *goqu.Database) error
+}
+
+// steps of migrations in order of their versions
+var steps = []*step{
+	{version: canonicalVersion, name: "canonical", run: canonicalize},
+}
+
+// sqlMigrator applying migrations of sql files interleaved by steps
+type sqlMigrator struct {
+	migrate  *migrate.Migrate
+	database *goqu.Database
+	steps    []*step
+}
+
+func (migrator *sqlMigrator) Up() error {
+	for _, step := range migrator.steps {
+		version, _, err := migrator.migrate.Version()
+		if err != nil && err != migrate.ErrNilVersion {
+			return err
+		}
+
+		// migrations after the step are applied, so is the step
+		if err == nil && version > step.version {
+			continue
+		}
+
+		if err := migrator.migrate.Migrate(step.version); err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+
+		if err := step.run(context.Background(), migrator.database); err != nil {
+			return fmt.Errorf("migrator: step '%s' of version %d: %w", step.name, step.version, err)
+		}
+	}
+
+	return migrator.migrate.Up()
+}
+
+// memory nothing to migrate for memory driver
+type memory struct{}
+
+func (migrator *memory) Up() error {
+	return migrate.ErrNoChange
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import "time"
+
+const (
+	PublisherFieldName   = "outbox.publisher"
+	FileFieldName        = "outbox.file"
+	HTTPURLFieldName     = "outbox.http.url"
+	HTTPTimeoutFieldName = "outbox.http.timeout"
+	IntervalFieldName    = "outbox.interval"
+	BatchSizeFieldName   = "outbox.batch_size"
+
+	PublisherDefault   = ""
+	FileDefault        = StdoutFile
+	HTTPTimeoutDefault = 5 * time.Second
+	IntervalDefault    = time.Second
+	BatchSizeDefault   = uint(100)
+)
+
+// publishers of Config.Publisher
+const (
+	FilePublisherName = "file"
+	HTTPPublisherName = "http"
+)
+
+// StdoutFile value of Config.File for publishing to stdout
+const StdoutFile = "-"
+
+type Config struct {
+	// Publisher name of publisher of events, empty disables relay
+	Publisher string
+	// File path of newline delimited json file of file publisher
+	File string
+	// HTTPURL url receiving events by POST requests of http publisher
+	HTTPURL string
+	// HTTPTimeout timeout of request of http publisher
+	HTTPTimeout time.Duration
+	// Interval between checks of unpublished events
+	Interval time.Duration
+	// BatchSize max count of events published by one check
+	BatchSize uint
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
@@ -0,0 +1,35 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// FilePublisher writing messages as newline delimited json
+type FilePublisher struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewFilePublisher(writer io.Writer) *FilePublisher {
+	return &FilePublisher{encoder: json.NewEncoder(writer)}
+}
+
+// openFile opening file for appending of messages, StdoutFile is stdout
+func openFile(path string) (io.Writer, error) {
+	if path == StdoutFile {
+		return os.Stdout, nil
+	}
+
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+func (publisher *FilePublisher) Publish(_ context.Context, message *Message) error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	return publisher.encoder.Encode(message)
+}
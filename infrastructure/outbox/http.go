@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-http-utils/headers"
+	"github.com/ldez/mimetype"
+	"net/http"
+	"strconv"
+)
+
+const (
+	EventIdHeaderName   = "X-Event-Id"
+	EventTypeHeaderName = "X-Event-Type"
+)
+
+// HTTPPublisher sending every message as json body of POST request, any status except 2xx is failure
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPPublisher(url string, client *http.Client) *HTTPPublisher {
+	return &HTTPPublisher{url: url, client: client}
+}
+
+func (publisher *HTTPPublisher) Publish(ctx context.Context, message *Message) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, publisher.url, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set(headers.ContentType, mimetype.ApplicationJSON)
+	request.Header.Set(EventIdHeaderName, strconv.FormatInt(message.Id, 10))
+	request.Header.Set(EventTypeHeaderName, message.Type)
+
+	response, err := publisher.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("outbox: %s responded with status %d", publisher.url, response.StatusCode)
+	}
+
+	return nil
+}
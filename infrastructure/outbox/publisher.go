@@ -0,0 +1,35 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Publisher delivering messages of events to subscribers, returned error leaves the event unpublished
+type Publisher interface {
+	Publish(ctx context.Context, message *Message) error
+}
+
+// Message published event of login
+type Message struct {
+	Id        int64           `json:"id"`
+	Sequence  int64           `json:"sequence"`
+	Type      string          `json:"type"`
+	Uuid      uuid.UUID       `json:"uuid"`
+	Login     json.RawMessage `json:"login"`
+	CreatedAt *time.Time      `json:"createdAt"`
+}
+
+func NewMessage(event *repository.Event) *Message {
+	return &Message{
+		Id:        event.Id,
+		Sequence:  event.Sequence,
+		Type:      event.Type,
+		Uuid:      event.LoginUuid,
+		Login:     json.RawMessage(event.Payload),
+		CreatedAt: event.CreatedAt,
+	}
+}
@@ -0,0 +1,160 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"time"
+)
+
+// Relay publishing events of outbox in order of their ids, every event is published at least once
+type Relay struct {
+	config    *Config
+	outbox    repository.Outbox
+	publisher Publisher
+	tracer    trace.Tracer
+	logger    log.Logger
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+) (*Relay, error) {
+	configurator.SetDefault(PublisherFieldName, PublisherDefault)
+	configurator.SetDefault(FileFieldName, FileDefault)
+	configurator.SetDefault(HTTPTimeoutFieldName, HTTPTimeoutDefault)
+	configurator.SetDefault(IntervalFieldName, IntervalDefault)
+	configurator.SetDefault(BatchSizeFieldName, BatchSizeDefault)
+
+	if publisher := configurator.GetString(PublisherFieldName); publisher != "" && config.Publisher == PublisherDefault {
+		config.Publisher = publisher
+	}
+
+	if file := configurator.GetString(FileFieldName); file != "" && config.File == FileDefault {
+		config.File = file
+	}
+
+	if url := configurator.GetString(HTTPURLFieldName); url != "" && config.HTTPURL == "" {
+		config.HTTPURL = url
+	}
+
+	if timeout := configurator.GetDuration(HTTPTimeoutFieldName); timeout > 0 && config.HTTPTimeout == HTTPTimeoutDefault {
+		config.HTTPTimeout = timeout
+	}
+
+	if interval := configurator.GetDuration(IntervalFieldName); interval > 0 && config.Interval == IntervalDefault {
+		config.Interval = interval
+	}
+
+	if batchSize := configurator.GetUint(BatchSizeFieldName); batchSize > 0 && config.BatchSize == BatchSizeDefault {
+		config.BatchSize = batchSize
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = IntervalDefault
+	}
+
+	if config.BatchSize == 0 {
+		config.BatchSize = BatchSizeDefault
+	}
+
+	var publisher Publisher
+
+	switch config.Publisher {
+	case PublisherDefault:
+	case FilePublisherName:
+		writer, err := openFile(config.File)
+		if err != nil {
+			return nil, err
+		}
+
+		publisher = NewFilePublisher(writer)
+	case HTTPPublisherName:
+		if config.HTTPURL == "" {
+			return nil, fmt.Errorf("outbox: %s must be set for http publisher", HTTPURLFieldName)
+		}
+
+		publisher = NewHTTPPublisher(config.HTTPURL, &http.Client{Timeout: config.HTTPTimeout})
+	default:
+		return nil, fmt.Errorf("outbox: publisher '%s' unknown", config.Publisher)
+	}
+
+	return NewRelay(config, repository, publisher, tracer, logger), nil
+}
+
+// NewRelay returns relay of outbox, nil publisher disables relay
+func NewRelay(config *Config, outbox repository.Outbox, publisher Publisher, tracer trace.Tracer, logger log.Logger) *Relay {
+	logger.Infof("outbox: publisher - '%s', interval - %s, batch size - %d", config.Publisher, config.Interval, config.BatchSize)
+
+	return &Relay{config: config, outbox: outbox, publisher: publisher, tracer: tracer, logger: logger}
+}
+
+// Run publishing unpublished events every Config.Interval until ctx is done
+func (relay *Relay) Run(ctx context.Context) error {
+	if relay.publisher == nil {
+		relay.logger.Info("outbox.relay: disabled")
+
+		return nil
+	}
+
+	ticker := time.NewTicker(relay.config.Interval)
+	defer ticker.Stop()
+
+	relay.logger.Info("outbox.relay: started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			relay.logger.Info("outbox.relay: shutdown")
+
+			return nil
+		case <-ticker.C:
+			for relay.relay(ctx) {
+			}
+		}
+	}
+}
+
+// relay publishing one batch of unpublished events, returns true if the next batch may be ready
+func (relay *Relay) relay(ctx context.Context) bool {
+	ctx, span := relay.tracer.Start(ctx, "relay")
+	defer span.End()
+
+	events, err := relay.outbox.Unpublished(ctx, relay.config.BatchSize)
+	if err != nil {
+		relay.logger.Error(err)
+		return false
+	}
+
+	ids := make([]int64, 0, len(events))
+
+	for _, event := range events {
+		if err := relay.publisher.Publish(ctx, NewMessage(event)); err != nil {
+			relay.logger.Error(err)
+			break
+		}
+
+		ids = append(ids, event.Id)
+	}
+
+	span.SetAttributes(attribute.Int("count", len(ids)))
+
+	if err := relay.outbox.MarkPublished(ctx, ids...); err != nil {
+		relay.logger.Error(err)
+		return false
+	}
+
+	if len(ids) > 0 {
+		relay.logger.Infof("outbox.relay: published %d events", len(ids))
+	}
+
+	return len(ids) == len(events) && uint(len(events)) == relay.config.BatchSize
+}
@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+// testPublisher recording published messages, fails every message after limit
+type testPublisher struct {
+	messages []*Message
+	limit    int
+}
+
+func (publisher *testPublisher) Publish(_ context.Context, message *Message) error {
+	if len(publisher.messages) >= publisher.limit {
+		return errors.New("unavailable")
+	}
+
+	publisher.messages = append(publisher.messages, message)
+
+	return nil
+}
+
+func TestRelay_Relay(t *testing.T) {
+	memory := repository.NewMemory(testTracer)
+	ctx := context.Background()
+
+	for _, login := range []string{"alice", "bob", "carol"} {
+		if _, err := memory.Insert(ctx, &repository.Login{Login: login}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publisher := &testPublisher{limit: 1}
+	relay := NewRelay(&Config{Publisher: "test", BatchSize: 2}, memory, publisher, testTracer, testLogger{t})
+
+	// failed event and events after it stay unpublished
+	if relay.relay(ctx) {
+		t.Fatal("next batch is ready after failed publishing")
+	}
+
+	unpublished, err := memory.Unpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(publisher.messages) != 1 || len(unpublished) != 2 {
+		t.Fatalf("published %d, unpublished %d, expected 1 and 2", len(publisher.messages), len(unpublished))
+	}
+
+	publisher.limit = 10
+
+	if !relay.relay(ctx) {
+		t.Fatal("next batch isn't ready after full batch")
+	}
+
+	if relay.relay(ctx) {
+		t.Fatal("next batch is ready after partial batch")
+	}
+
+	for index, message := range publisher.messages {
+		if message.Type != repository.LoginCreatedEvent || message.Sequence != publisher.messages[0].Sequence+int64(index) {
+			t.Fatalf("message %d: %s of sequence %d, expected created events in order", index, message.Type, message.Sequence)
+		}
+	}
+
+	if len(publisher.messages) != 3 {
+		t.Fatalf("published %d, expected 3", len(publisher.messages))
+	}
+}
@@ -0,0 +1,34 @@
+package policy
+
+const (
+	MinLengthFieldName         = "login.policy.min_length"
+	MaxLengthFieldName         = "login.policy.max_length"
+	ClassesFieldName           = "login.policy.classes"
+	PatternFieldName           = "login.policy.pattern"
+	ForbiddenPrefixesFieldName = "login.policy.forbidden_prefixes"
+
+	MinLengthDefault = 1
+	// MaxLengthDefault size of login column
+	MaxLengthDefault = 56
+	PatternDefault   = ""
+)
+
+// ClassesDefault allowed character classes by default, everything except control and format characters
+var ClassesDefault = []string{LetterClass, MarkClass, DigitClass, PunctClass, SymbolClass, SpaceClass}
+
+type Config struct {
+	// MinLength minimal length of login in characters
+	MinLength uint
+	// MaxLength maximal length of login in characters
+	MaxLength uint
+	// Classes allowed character classes of login
+	Classes []string
+	// Pattern regular expression which login must match, empty pattern is not checked
+	Pattern string
+	// ForbiddenPrefixes prefixes which login can't start with, compared in canonical form
+	ForbiddenPrefixes []string
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// character classes of Config.Classes
+const (
+	LetterClass = "letter"
+	MarkClass   = "mark"
+	DigitClass  = "digit"
+	PunctClass  = "punct"
+	SymbolClass = "symbol"
+	SpaceClass  = "space"
+)
+
+// rules of Violation
+const (
+	MinLengthRule       = "min_length"
+	MaxLengthRule       = "max_length"
+	ClassesRule         = "classes"
+	PatternRule         = "pattern"
+	ForbiddenPrefixRule = "forbidden_prefix"
+)
+
+var classes = map[string]*unicode.RangeTable{
+	LetterClass: unicode.L,
+	MarkClass:   unicode.M,
+	DigitClass:  unicode.Nd,
+	PunctClass:  unicode.P,
+	SymbolClass: unicode.S,
+	SpaceClass:  unicode.White_Space,
+}
+
+// Violation of LoginPolicy rule
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// LoginPolicy checking logins by rules of Config
+type LoginPolicy struct {
+	config  *Config
+	classes []*unicode.RangeTable
+	pattern *regexp.Regexp
+}
+
+func WithConfigurator(configurator configurator.Configurator, config *Config, logger log.Logger) (*LoginPolicy, error) {
+	configurator.SetDefault(MinLengthFieldName, MinLengthDefault)
+	configurator.SetDefault(MaxLengthFieldName, MaxLengthDefault)
+	configurator.SetDefault(ClassesFieldName, ClassesDefault)
+	configurator.SetDefault(PatternFieldName, PatternDefault)
+	configurator.SetDefault(ForbiddenPrefixesFieldName, []string{})
+
+	config.MinLength = configurator.GetUint(MinLengthFieldName)
+	config.MaxLength = configurator.GetUint(MaxLengthFieldName)
+	config.Classes = configurator.GetStringSlice(ClassesFieldName)
+	config.Pattern = configurator.GetString(PatternFieldName)
+	config.ForbiddenPrefixes = configurator.GetStringSlice(ForbiddenPrefixesFieldName)
+
+	if config.MaxLength == 0 {
+		config.MaxLength = MaxLengthDefault
+	}
+
+	if config.MinLength == 0 {
+		config.MinLength = MinLengthDefault
+	}
+
+	return NewLoginPolicy(config, logger)
+}
+
+func NewLoginPolicy(config *Config, logger log.Logger) (*LoginPolicy, error) {
+	if config.MaxLength > MaxLengthDefault {
+		return nil, fmt.Errorf("login.policy: max length %d exceeds size of login column %d", config.MaxLength, MaxLengthDefault)
+	}
+
+	if config.MinLength > config.MaxLength {
+		return nil, fmt.Errorf("login.policy: min length %d is greater than max length %d", config.MinLength, config.MaxLength)
+	}
+
+	policy := &LoginPolicy{config: config}
+
+	for _, name := range config.Classes {
+		class, ok := classes[name]
+		if !ok {
+			return nil, fmt.Errorf("login.policy: unknown character class '%s'", name)
+		}
+
+		policy.classes = append(policy.classes, class)
+	}
+
+	if config.Pattern != "" {
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("login.policy: %w", err)
+		}
+
+		policy.pattern = pattern
+	}
+
+	logger.Infof("login.policy: length - %d..%d", config.MinLength, config.MaxLength)
+	logger.Infof("login.policy: classes - %s", strings.Join(config.Classes, ", "))
+
+	if config.Pattern != "" {
+		logger.Infof("login.policy: pattern - %s", config.Pattern)
+	}
+
+	if len(config.ForbiddenPrefixes) > 0 {
+		logger.Infof("login.policy: forbidden prefixes - %s", strings.Join(config.ForbiddenPrefixes, ", "))
+	}
+
+	return policy, nil
+}
+
+// Check returns every violated rule of login, empty result means login is allowed
+func (policy *LoginPolicy) Check(login string) []*Violation {
+	var violations []*Violation
+
+	canonical := repository.Canonical(login)
+
+	length := uint(utf8.RuneCountInString(login))
+	if length < policy.config.MinLength {
+		violations = append(violations, &Violation{
+			Rule:    MinLengthRule,
+			Message: fmt.Sprintf("must be at least %d characters long", policy.config.MinLength),
+		})
+	}
+
+	if length > policy.config.MaxLength {
+		violations = append(violations, &Violation{
+			Rule:    MaxLengthRule,
+			Message: fmt.Sprintf("must be at most %d characters long", policy.config.MaxLength),
+		})
+	} else if utf8.RuneCountInString(canonical) > repository.CanonicalMaxLength {
+		violations = append(violations, &Violation{
+			Rule:    MaxLengthRule,
+			Message: fmt.Sprintf("must be at most %d characters long in canonical form", repository.CanonicalMaxLength),
+		})
+	}
+
+	for _, char := range login {
+		if !unicode.IsOneOf(policy.classes, char) {
+			violations = append(violations, &Violation{
+				Rule:    ClassesRule,
+				Message: fmt.Sprintf("character %q is not allowed, allowed classes: %s", char, strings.Join(policy.config.Classes, ", ")),
+			})
+
+			break
+		}
+	}
+
+	if policy.pattern != nil && !policy.pattern.MatchString(login) {
+		violations = append(violations, &Violation{
+			Rule:    PatternRule,
+			Message: fmt.Sprintf("must match pattern %s", policy.config.Pattern),
+		})
+	}
+
+	for _, prefix := range policy.config.ForbiddenPrefixes {
+		if prefix != "" && strings.HasPrefix(canonical, repository.Canonical(prefix)) {
+			violations = append(violations, &Violation{
+				Rule:    ForbiddenPrefixRule,
+				Message: fmt.Sprintf("must not start with '%s'", prefix),
+			})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+// newTestPolicy returns policy of config completed by default length and classes
+func newTestPolicy(t *testing.T, config *Config) *LoginPolicy {
+	t.Helper()
+
+	if config.MinLength == 0 {
+		config.MinLength = MinLengthDefault
+	}
+
+	if config.MaxLength == 0 {
+		config.MaxLength = MaxLengthDefault
+	}
+
+	if config.Classes == nil {
+		config.Classes = ClassesDefault
+	}
+
+	policy, err := NewLoginPolicy(config, testLogger{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return policy
+}
+
+// rules returns rules of violations in their order
+func rules(violations []*Violation) []string {
+	rules := make([]string, len(violations))
+	for index, violation := range violations {
+		rules[index] = violation.Rule
+	}
+
+	return rules
+}
+
+func TestLoginPolicy_Check(t *testing.T) {
+	policy := newTestPolicy(t, &Config{
+		MinLength:         3,
+		MaxLength:         8,
+		Classes:           []string{LetterClass, DigitClass},
+		Pattern:           `^\p{Ll}`,
+		ForbiddenPrefixes: []string{"Admin"},
+	})
+
+	for login, expected := range map[string][]string{
+		"alice":     {},
+		"élise":     {},
+		"al":        {MinLengthRule},
+		"alice1234": {MaxLengthRule},
+		"ali ce":    {ClassesRule},
+		"1alice":    {PatternRule},
+		"adminbob":  {ForbiddenPrefixRule},
+		"ADMIN":     {PatternRule, ForbiddenPrefixRule},
+		"a-":        {MinLengthRule, ClassesRule},
+	} {
+		if actual := rules(policy.Check(login)); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Errorf("violated rules of '%s' %v, expected %v", login, actual, expected)
+		}
+	}
+}
+
+func TestLoginPolicy_CheckCanonicalLength(t *testing.T) {
+	policy := newTestPolicy(t, &Config{})
+
+	// every character expands to 18 characters by NFKC
+	login := strings.Repeat("ﷺ", MaxLengthDefault)
+
+	if length := utf8.RuneCountInString(repository.Canonical(login)); length <= repository.CanonicalMaxLength {
+		t.Fatalf("canonical form of login has %d characters, expected more than %d", length, repository.CanonicalMaxLength)
+	}
+
+	if actual := rules(policy.Check(login)); fmt.Sprint(actual) != fmt.Sprint([]string{MaxLengthRule}) {
+		t.Fatalf("violated rules %v, expected max length of canonical form", actual)
+	}
+
+	if actual := rules(policy.Check(strings.Repeat("ﷺ", 14))); len(actual) != 0 {
+		t.Fatalf("violated rules of login fitting column %v, expected none", actual)
+	}
+}
+
+func TestNewLoginPolicy(t *testing.T) {
+	for name, config := range map[string]*Config{
+		"max length of column": {MinLength: 1, MaxLength: MaxLengthDefault + 1},
+		"min over max":         {MinLength: 10, MaxLength: 5},
+		"unknown class":        {MinLength: 1, MaxLength: 5, Classes: []string{"emoji"}},
+		"invalid pattern":      {MinLength: 1, MaxLength: 5, Pattern: "("},
+	} {
+		if _, err := NewLoginPolicy(config, testLogger{t}); err == nil {
+			t.Errorf("policy of %s is created, expected error", name)
+		}
+	}
+}
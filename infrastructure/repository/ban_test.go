@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/time"
+	"testing"
+	stdTime "time"
+)
+
+func TestRepository_BanRepeated(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		until := time.NowUTC().Add(stdTime.Hour)
+
+		for attempt := 0; attempt < 2; attempt++ {
+			found, err := repository.BanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin", Until: &until, Reason: "spam"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !found {
+				t.Fatalf("ban %d: login not found", attempt+1)
+			}
+		}
+
+		banned, err := repository.FindByUuid(ctx, inserted.Uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !banned.Banned || banned.Version != inserted.Version+1 {
+			t.Fatalf("after repeated ban: banned %t, version %d, expected banned of version %d", banned.Banned, banned.Version, inserted.Version+1)
+		}
+
+		bans, err := repository.CountBans(ctx, inserted.Uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bans != 1 {
+			t.Fatalf("bans in history %d, expected 1", bans)
+		}
+
+		events, err := repository.AllEventsAfter(ctx, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(events) != 2 || events[1].Type != LoginBannedEvent {
+			t.Fatalf("events %v, expected created and banned", eventTypes(events))
+		}
+
+		// ban with another reason is a change
+		if _, err := repository.BanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin", Until: &until, Reason: "abuse"}); err != nil {
+			t.Fatal(err)
+		}
+
+		rebanned, err := repository.FindByUuid(ctx, inserted.Uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rebanned.BanReason != "abuse" || rebanned.Version != banned.Version+1 {
+			t.Fatalf("after ban by another reason: reason '%s', version %d", rebanned.BanReason, rebanned.Version)
+		}
+	})
+}
+
+// eventTypes returns types of events in their order
+func eventTypes(events []*Event) []string {
+	types := make([]string, len(events))
+	for index, event := range events {
+		types[index] = event.Type
+	}
+
+	return types
+}
+
+func TestRepository_UnbanEvents(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// unban of login which isn't banned changes nothing
+		if _, err := repository.UnbanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repository.BanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin", Reason: "spam"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repository.UnbanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin"}); err != nil {
+			t.Fatal(err)
+		}
+
+		expired := time.NowUTC().Add(-stdTime.Minute)
+
+		if _, err := repository.BanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin", Until: &expired, Reason: "spam"}); err != nil {
+			t.Fatal(err)
+		}
+
+		count, err := repository.UnbanExpired(ctx, time.NowUTC())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 1 {
+			t.Fatalf("unbanned by expiry %d, expected 1", count)
+		}
+
+		events, err := repository.AllEventsAfter(ctx, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{LoginCreatedEvent, LoginBannedEvent, LoginUnbannedEvent, LoginBannedEvent, LoginUnbannedEvent}
+		if fmt.Sprint(eventTypes(events)) != fmt.Sprint(expected) {
+			t.Fatalf("events %v, expected %v", eventTypes(events), expected)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+// cached decorator of Repository serving Finder from lru cache, invalidated by writes of logins;
+// concurrent misses of the same key are coalesced into one lookup of decorated Repository
+type cached struct {
+	Repository
+
+	cache  *lru
+	group  *singleflight.Group
+	tracer trace.Tracer
+
+	// tx is set when decorated Repository is scoped by transaction, lookups bypass the cache
+	// and invalidations are postponed until the end of transaction
+	tx *cachedTx
+}
+
+// cachedTx logins written by transaction
+type cachedTx struct {
+	mutex  sync.Mutex
+	logins []*Login
+	purge  bool
+}
+
+func NewCached(config *CacheConfig, repository Repository, tracer trace.Tracer) Repository {
+	return &cached{
+		Repository: repository,
+		cache:      newLru(config.Size, config.TTL),
+		group:      &singleflight.Group{},
+		tracer:     tracer,
+	}
+}
+
+func (repository *cached) FindByUuid(ctx context.Context, uuid uuid.UUID) (*Login, error) {
+	if repository.tx != nil {
+		return repository.Repository.FindByUuid(ctx, uuid)
+	}
+
+	return repository.find(ctx, uuidKey(TenantFrom(ctx), uuid), func(ctx context.Context) (*Login, error) {
+		return repository.Repository.FindByUuid(ctx, uuid)
+	})
+}
+
+func (repository *cached) FindByLogin(ctx context.Context, login string) (*Login, error) {
+	if repository.tx != nil {
+		return repository.Repository.FindByLogin(ctx, login)
+	}
+
+	return repository.find(ctx, loginKey(TenantFrom(ctx), Canonical(login)), func(ctx context.Context) (*Login, error) {
+		return repository.Repository.FindByLogin(ctx, login)
+	})
+}
+
+// find returns login cached by key or loaded by load, db.RecordNotFoundError is cached as well
+func (repository *cached) find(ctx context.Context, key string, load func(ctx context.Context) (*Login, error)) (*Login, error) {
+	ctx, span := repository.tracer.Start(ctx, "find")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("key", key),
+		attribute.String("repository", "cache"),
+	)
+
+	if login, ok := repository.cache.get(key); ok {
+		span.SetAttributes(attribute.Bool("hit", true))
+
+		if login == nil {
+			return nil, db.RecordNotFoundError
+		}
+
+		return login, nil
+	}
+
+	span.SetAttributes(attribute.Bool("hit", false))
+
+	value, err, _ := repository.group.Do(key, func() (interface{}, error) {
+		generation := repository.cache.current()
+
+		// replica may lag behind invalidating write, so cached lookups are loaded from primary
+		login, err := load(WithPrimary(ctx))
+		if err != nil && err != db.RecordNotFoundError {
+			return nil, err
+		}
+
+		repository.cache.set(key, login, generation)
+
+		return login, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*Login).clone(), nil
+}
+
+func (repository *cached) Insert(ctx context.Context, login *Login) (*Login, error) {
+	login, err := repository.Repository.Insert(ctx, login)
+	if err == nil {
+		repository.invalidate(ctx, login)
+	}
+
+	return login, err
+}
+
+func (repository *cached) Update(ctx context.Context, login *Login) (*Login, error) {
+	updated, err := repository.Repository.Update(ctx, login)
+
+	// former canonical login is invalidated through uuid of login
+	repository.invalidate(ctx, login)
+
+	return updated, err
+}
+
+func (repository *cached) InsertBatch(ctx context.Context, logins []*Login) ([]error, error) {
+	errs, err := repository.Repository.InsertBatch(ctx, logins)
+	if err == nil {
+		repository.invalidate(ctx, logins...)
+	}
+
+	return errs, err
+}
+
+func (repository *cached) BanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error) {
+	banned, err := repository.Repository.BanByUuid(ctx, uuid, ban)
+	repository.invalidate(ctx, &Login{Uuid: uuid})
+
+	return banned, err
+}
+
+func (repository *cached) UnbanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error) {
+	unbanned, err := repository.Repository.UnbanByUuid(ctx, uuid, ban)
+	repository.invalidate(ctx, &Login{Uuid: uuid})
+
+	return unbanned, err
+}
+
+func (repository *cached) UnbanExpired(ctx context.Context, now time.Time) (int64, error) {
+	count, err := repository.Repository.UnbanExpired(ctx, now)
+	if count > 0 {
+		repository.purge()
+	}
+
+	return count, err
+}
+
+func (repository *cached) Transaction(ctx context.Context, fn func(repository Repository) error) error {
+	if repository.tx != nil {
+		return fn(repository)
+	}
+
+	tx := &cachedTx{}
+
+	err := repository.Repository.Transaction(ctx, func(scoped Repository) error {
+		return fn(&cached{Repository: scoped, cache: repository.cache, group: repository.group, tracer: repository.tracer, tx: tx})
+	})
+
+	repository.flush(tx)
+
+	return err
+}
+
+func (repository *cached) Snapshot(ctx context.Context, fn func(repository Repository) error) error {
+	if repository.tx != nil {
+		return fn(repository)
+	}
+
+	tx := &cachedTx{}
+
+	err := repository.Repository.Snapshot(ctx, func(scoped Repository) error {
+		return fn(&cached{Repository: scoped, cache: repository.cache, group: repository.group, tracer: repository.tracer, tx: tx})
+	})
+
+	repository.flush(tx)
+
+	return err
+}
+
+// invalidate removing lookups of logins of tenant of ctx by uuid and canonical form of login from cache,
+// inside of transaction they are removed by flush
+func (repository *cached) invalidate(ctx context.Context, logins ...*Login) {
+	tenant := TenantFrom(ctx)
+
+	keys := make([]*Login, len(logins))
+	for index, login := range logins {
+		keys[index] = &Login{Uuid: login.Uuid, Tenant: tenant, LoginCanonical: Canonical(login.Login)}
+	}
+
+	if repository.tx == nil {
+		repository.cache.invalidate(keys...)
+		return
+	}
+
+	repository.tx.mutex.Lock()
+	defer repository.tx.mutex.Unlock()
+
+	repository.tx.logins = append(repository.tx.logins, keys...)
+}
+
+// purge removing every lookup from cache, inside of transaction it is done by flush
+func (repository *cached) purge() {
+	if repository.tx == nil {
+		repository.cache.purge()
+		return
+	}
+
+	repository.tx.mutex.Lock()
+	defer repository.tx.mutex.Unlock()
+
+	repository.tx.purge = true
+}
+
+// flush removing lookups of logins written by finished transaction
+func (repository *cached) flush(tx *cachedTx) {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+
+	if tx.purge {
+		repository.cache.purge()
+		return
+	}
+
+	if len(tx.logins) > 0 {
+		repository.cache.invalidate(tx.logins...)
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+import "time"
+
+const (
+	CacheSizeFieldName = "repository.cache.size"
+	CacheTTLFieldName  = "repository.cache.ttl"
+
+	CacheSizeDefault = 0
+	CacheTTLDefault  = time.Minute
+)
+
+type CacheConfig struct {
+	// Size max count of cached lookups of Finder, zero disables cache
+	Size int
+	// TTL time of life of cached lookup
+	TTL time.Duration
+}
+
+func NewCacheConfig() *CacheConfig {
+	return &CacheConfig{}
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"container/list"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/google/uuid"
+	"sync"
+	stdTime "time"
+)
+
+// lruEntry cached lookup, login is nil for lookup of absent login
+type lruEntry struct {
+	key       string
+	login     *Login
+	expiresAt stdTime.Time
+}
+
+// lru thread-safe cache of lookups with limited size and time of life of entries,
+// every invalidation increments generation so lookups loaded before it are not stored
+type lru struct {
+	mutex sync.Mutex
+
+	size int
+	ttl  stdTime.Duration
+
+	order   *list.List
+	entries map[string]*list.Element
+	byUuid  map[uuid.UUID]map[string]struct{} // keys of entries by uuid of their login
+
+	generation uint64
+}
+
+func newLru(size int, ttl stdTime.Duration) *lru {
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+		byUuid:  map[uuid.UUID]map[string]struct{}{},
+	}
+}
+
+func uuidKey(tenant string, uuid uuid.UUID) string {
+	return "uuid:" + tenant + "/" + uuid.String()
+}
+
+func loginKey(tenant string, canonical string) string {
+	return "login:" + tenant + "/" + canonical
+}
+
+// get returns copy of cached login, nil for cached absent login and false if key isn't cached
+func (cache *lru) get(key string) (*Login, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.After(time.NowUTC()) {
+		cache.remove(element)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+
+	if entry.login == nil {
+		return nil, true
+	}
+
+	return entry.login.clone(), true
+}
+
+// current returns current generation, it must be taken before loading of lookup stored by set
+func (cache *lru) current() uint64 {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.generation
+}
+
+// set caching copy of login by key, if no invalidation happened since generation
+func (cache *lru) set(key string, login *Login, generation uint64) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if generation != cache.generation {
+		return
+	}
+
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element)
+	}
+
+	entry := &lruEntry{key: key, expiresAt: time.NowUTC().Add(cache.ttl)}
+	if login != nil {
+		entry.login = login.clone()
+
+		if _, ok := cache.byUuid[login.Uuid]; !ok {
+			cache.byUuid[login.Uuid] = map[string]struct{}{}
+		}
+
+		cache.byUuid[login.Uuid][key] = struct{}{}
+	}
+
+	cache.entries[key] = cache.order.PushFront(entry)
+
+	for cache.order.Len() > cache.size {
+		cache.remove(cache.order.Back())
+	}
+}
+
+// invalidate removing every lookup of logins by their uuid and canonical login,
+// including lookups of former canonical login
+func (cache *lru) invalidate(logins ...*Login) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.generation++
+
+	for _, login := range logins {
+		for key := range cache.byUuid[login.Uuid] {
+			cache.remove(cache.entries[key])
+		}
+
+		for _, key := range []string{uuidKey(login.Tenant, login.Uuid), loginKey(login.Tenant, login.LoginCanonical)} {
+			if element, ok := cache.entries[key]; ok {
+				cache.remove(element)
+			}
+		}
+	}
+}
+
+// purge removing every lookup
+func (cache *lru) purge() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.generation++
+
+	cache.order.Init()
+	cache.entries = map[string]*list.Element{}
+	cache.byUuid = map[uuid.UUID]map[string]struct{}{}
+}
+
+// remove removing element from order and indexes, lock must be held
+func (cache *lru) remove(element *list.Element) {
+	entry := cache.order.Remove(element).(*lruEntry)
+
+	delete(cache.entries, entry.key)
+
+	if entry.login == nil {
+		return
+	}
+
+	keys := cache.byUuid[entry.login.Uuid]
+	delete(keys, entry.key)
+
+	if len(keys) == 0 {
+		delete(cache.byUuid, entry.login.Uuid)
+	}
+}
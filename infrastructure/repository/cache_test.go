@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"testing"
+	stdTime "time"
+)
+
+// newTestCached returns cache decorating memory repository, the memory repository writing bypassing the cache
+// and login alice inserted through the cache
+func newTestCached(t *testing.T) (Repository, Repository, *Login) {
+	t.Helper()
+
+	memory := NewMemory(testTracer)
+	cached := NewCached(&CacheConfig{Size: 10, TTL: stdTime.Minute}, memory, testTracer)
+
+	login, err := cached.Insert(context.Background(), &Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cached, memory, login
+}
+
+// findBoth returns login found by uuid and by login of cached
+func findBoth(t *testing.T, cached Repository, login *Login) (*Login, *Login) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	byUuid, err := cached.FindByUuid(ctx, login.Uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byLogin, err := cached.FindByLogin(ctx, login.Login)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return byUuid, byLogin
+}
+
+func TestCached_Hit(t *testing.T) {
+	cached, memory, login := newTestCached(t)
+	ctx := context.Background()
+
+	findBoth(t, cached, login)
+
+	// write bypassing the cache isn't seen until invalidation
+	if _, err := memory.BanByUuid(ctx, login.Uuid, &Ban{Actor: "admin", Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if byUuid, byLogin := findBoth(t, cached, login); byUuid.Banned || byLogin.Banned {
+		t.Fatal("lookups aren't served from cache")
+	}
+}
+
+func TestCached_Invalidation(t *testing.T) {
+	for name, write := range map[string]func(ctx context.Context, repository Repository, login *Login) error{
+		"ban": func(ctx context.Context, repository Repository, login *Login) error {
+			_, err := repository.BanByUuid(ctx, login.Uuid, &Ban{Actor: "admin", Reason: "spam"})
+			return err
+		},
+		"ban in transaction": func(ctx context.Context, repository Repository, login *Login) error {
+			return repository.Transaction(ctx, func(tx Repository) error {
+				_, err := tx.BanByUuid(ctx, login.Uuid, &Ban{Actor: "admin", Reason: "spam"})
+				return err
+			})
+		},
+		"update": func(ctx context.Context, repository Repository, login *Login) error {
+			updated := *login
+			updated.BanReason = "updated"
+
+			_, err := repository.Update(ctx, &updated)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cached, _, login := newTestCached(t)
+
+			findBoth(t, cached, login)
+
+			if err := write(context.Background(), cached, login); err != nil {
+				t.Fatal(err)
+			}
+
+			byUuid, byLogin := findBoth(t, cached, login)
+			if byUuid.Version != login.Version+1 || byLogin.Version != login.Version+1 {
+				t.Fatalf("versions of lookups after write %d and %d, expected %d", byUuid.Version, byLogin.Version, login.Version+1)
+			}
+		})
+	}
+}
+
+func TestCached_InvalidationUnban(t *testing.T) {
+	cached, _, login := newTestCached(t)
+	ctx := context.Background()
+
+	expired := time.NowUTC().Add(-stdTime.Minute)
+
+	if _, err := cached.BanByUuid(ctx, login.Uuid, &Ban{Actor: "admin", Until: &expired, Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if byUuid, byLogin := findBoth(t, cached, login); !byUuid.Banned || !byLogin.Banned {
+		t.Fatal("lookups after ban aren't banned")
+	}
+
+	if _, err := cached.UnbanExpired(ctx, time.NowUTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	if byUuid, byLogin := findBoth(t, cached, login); byUuid.Banned || byLogin.Banned {
+		t.Fatal("lookups after unban by expiry are banned")
+	}
+
+	if _, err := cached.BanByUuid(ctx, login.Uuid, &Ban{Actor: "admin", Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+
+	findBoth(t, cached, login)
+
+	if _, err := cached.UnbanByUuid(ctx, login.Uuid, &Ban{Actor: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if byUuid, byLogin := findBoth(t, cached, login); byUuid.Banned || byLogin.Banned {
+		t.Fatal("lookups after unban are banned")
+	}
+}
+
+func TestCached_InvalidationRename(t *testing.T) {
+	cached, _, login := newTestCached(t)
+	ctx := context.Background()
+
+	findBoth(t, cached, login)
+
+	renamed := *login
+	renamed.Login = "bob"
+
+	if _, err := cached.Update(ctx, &renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cached.FindByLogin(ctx, "alice"); err != db.RecordNotFoundError {
+		t.Fatalf("find of former login: %v, expected db.RecordNotFoundError", err)
+	}
+
+	if found, err := cached.FindByUuid(ctx, login.Uuid); err != nil || found.Login != "bob" {
+		t.Fatalf("find by uuid after rename: %v, expected 'bob'", err)
+	}
+
+	if _, err := cached.FindByLogin(ctx, "carol"); err != db.RecordNotFoundError {
+		t.Fatalf("find of unknown login: %v, expected db.RecordNotFoundError", err)
+	}
+
+	if _, err := cached.Insert(ctx, &Login{Login: "carol"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// cached absence is invalidated by insert
+	if _, err := cached.FindByLogin(ctx, "carol"); err != nil {
+		t.Fatalf("find of inserted login: %v", err)
+	}
+}
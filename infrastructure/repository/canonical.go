@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+// CanonicalMaxLength size of login_canonical column in characters, NFKC can expand login past it
+const CanonicalMaxLength = 255
+
+// Canonical returns canonical form of login used for lookups and uniqueness:
+// trimmed of whitespace, normalized to NFKC and case folded
+func Canonical(login string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(strings.TrimSpace(login))))
+}
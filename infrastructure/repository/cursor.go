@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	InvalidCursorError = errors.New("invalid cursor")
+)
+
+// Cursor position after which the next page of keyset pagination starts,
+// keeps sort of Filter and value of sorted field of the last login
+type Cursor struct {
+	Id    int64      `json:"id"`
+	Sort  string     `json:"sort,omitempty"`
+	Desc  bool       `json:"desc,omitempty"`
+	Login string     `json:"login,omitempty"`
+	Time  *time.Time `json:"time,omitempty"`
+}
+
+// NewCursor returns Cursor pointing to the login in order of the filter
+func NewCursor(login *Login, filter *Filter) *Cursor {
+	cursor := &Cursor{Id: login.Id, Desc: filter.Desc}
+
+	switch filter.sort() {
+	case LoginSort:
+		cursor.Sort = LoginSort
+		cursor.Login = login.LoginCanonical
+	case CreatedSort:
+		cursor.Sort = CreatedSort
+		cursor.Time = login.CreatedAt
+	case UpdatedSort:
+		cursor.Sort = UpdatedSort
+		cursor.Time = login.CreatedAt
+
+		if login.UpdateAt != nil {
+			cursor.Time = login.UpdateAt
+		}
+	}
+
+	return cursor
+}
+
+// DecodeCursor parsing opaque value returned by Cursor.Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, InvalidCursorError
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(content, cursor); err != nil {
+		return nil, InvalidCursorError
+	}
+
+	return cursor, nil
+}
+
+// Encode returns opaque value of cursor for clients
+func (cursor *Cursor) Encode() string {
+	content, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(content)
+}
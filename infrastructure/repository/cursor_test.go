@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// walk returns logins of every page of filter by limit, the first page is read by Page, the next ones by cursors
+// of the last login of previous page passed through their encoded form like clients do
+func walk(t *testing.T, ctx context.Context, repository Repository, filter *Filter, limit uint, between func()) []string {
+	t.Helper()
+
+	var logins []string
+
+	page, err := repository.Page(ctx, filter, 0, limit)
+
+	for err == nil {
+		for _, login := range page {
+			logins = append(logins, login.Login)
+		}
+
+		if uint(len(page)) < limit {
+			return logins
+		}
+
+		if between != nil {
+			between()
+		}
+
+		cursor, decodeErr := DecodeCursor(NewCursor(page[len(page)-1], filter).Encode())
+		if decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+
+		page, err = repository.PageByCursor(ctx, filter, cursor, limit)
+	}
+
+	if err != io.EOF {
+		t.Fatal(err)
+	}
+
+	return logins
+}
+
+func TestCursor_Encode(t *testing.T) {
+	cursor := &Cursor{Id: 42}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *decoded != *cursor {
+		t.Fatalf("decoded cursor %+v, expected %+v", decoded, cursor)
+	}
+
+	for _, value := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := DecodeCursor(value); !errors.Is(err, InvalidCursorError) {
+			t.Errorf("decode of '%s': %v, expected InvalidCursorError", value, err)
+		}
+	}
+}
+
+func TestRepository_PageByCursor(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		for index := 0; index < 5; index++ {
+			if _, err := repository.Insert(ctx, &Login{Login: fmt.Sprintf("login%d", index)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		inserted := false
+
+		// login inserted during the walk is appended by id, pages already read aren't shifted
+		logins := walk(t, ctx, repository, &Filter{}, 2, func() {
+			if inserted {
+				return
+			}
+
+			inserted = true
+
+			if _, err := repository.Insert(ctx, &Login{Login: "late"}); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		if expected := "[login0 login1 login2 login3 login4 late]"; fmt.Sprint(logins) != expected {
+			t.Fatalf("walked logins %v, expected %s", logins, expected)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// VersionConflictError returned when login was changed after reading of the expected version
+	VersionConflictError = errors.New("version conflict")
+	// InvalidPatternError returned when pattern of reserved logins is empty or malformed
+	InvalidPatternError = errors.New("invalid reserved pattern")
+	// InvalidSearchError returned when search query is empty or mode is unknown
+	InvalidSearchError = errors.New("invalid search")
+	// InvalidFilterError returned when sort of filter is unknown or range of filter is empty
+	InvalidFilterError = errors.New("invalid filter")
+	// InvalidTenantError returned when tenant is empty or malformed
+	InvalidTenantError = errors.New("invalid tenant")
+
+	// ErrDuplicateLogin returned when login is already taken by another record
+	ErrDuplicateLogin = &DuplicateError{Field: "login"}
+	// ErrDuplicateUuid returned when uuid is already taken by another record
+	ErrDuplicateUuid = &DuplicateError{Field: "uuid"}
+	// ErrDuplicateReserved returned when pattern is already reserved
+	ErrDuplicateReserved = &DuplicateError{Field: "pattern"}
+)
+
+// DuplicateError violation of unique field of login, compare with errors.Is and read the field with errors.As
+type DuplicateError struct {
+	Field string
+}
+
+func (err *DuplicateError) Error() string {
+	return fmt.Sprintf("repository: %s already exists", err.Field)
+}
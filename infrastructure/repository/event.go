@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// types of Event
+const (
+	LoginCreatedEvent  = "login.created"
+	LoginUpdatedEvent  = "login.updated"
+	LoginBannedEvent   = "login.banned"
+	LoginUnbannedEvent = "login.unbanned"
+)
+
+// Event change of login written to outbox in the same transaction as the change itself
+type Event struct {
+	Id          int64      `db:"-"`
+	Sequence    int64      `db:"sequence"`
+	Tenant      string     `db:"tenant"`
+	Type        string     `db:"type"`
+	LoginUuid   uuid.UUID  `db:"login_uuid"`
+	Payload     string     `db:"payload"` // json of EventLogin
+	CreatedAt   *time.Time `db:"created_at"`
+	PublishedAt *time.Time `db:"published_at"`
+}
+
+// EventLogin state of login after the change, payload of Event
+type EventLogin struct {
+	Uuid        uuid.UUID       `json:"uuid"`
+	Tenant      string          `json:"tenant"`
+	Login       string          `json:"login"`
+	Banned      bool            `json:"banned"`
+	BannedUntil *time.Time      `json:"bannedUntil"`
+	BanReason   string          `json:"banReason"`
+	Version     int64           `json:"version"`
+	Revision    int64           `json:"revision"`
+	Metadata    json.RawMessage `json:"metadata"`
+	CreatedAt   *time.Time      `json:"createdAt"`
+	UpdateAt    *time.Time      `json:"updateAt"`
+}
+
+// NewEventLogin returns state of login in form of payload of Event
+func NewEventLogin(login *Login) *EventLogin {
+	return &EventLogin{
+		Uuid:        login.Uuid,
+		Tenant:      login.Tenant,
+		Login:       login.Login,
+		Banned:      login.Banned,
+		BannedUntil: login.BannedUntil,
+		BanReason:   login.BanReason,
+		Version:     login.Version,
+		Revision:    login.Revision,
+		Metadata:    RawMetadata(login),
+		CreatedAt:   login.CreatedAt,
+		UpdateAt:    login.UpdateAt,
+	}
+}
+
+func newEvent(eventType string, login *Login, now time.Time) (*Event, error) {
+	payload, err := json.Marshal(NewEventLogin(login))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{Type: eventType, Tenant: login.Tenant, LoginUuid: login.Uuid, Payload: string(payload), CreatedAt: &now}, nil
+}
+
+// updatedEvent returns type of Event of update of login from stored state
+func updatedEvent(stored *Login, login *Login) string {
+	switch {
+	case login.Banned && !stored.Banned:
+		return LoginBannedEvent
+	case !login.Banned && stored.Banned:
+		return LoginUnbannedEvent
+	}
+
+	return LoginUpdatedEvent
+}
+
+// clone returns deep copy of event, used by memory repository to not share stored records
+func (event *Event) clone() *Event {
+	clone := *event
+
+	if event.CreatedAt != nil {
+		createdAt := *event.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	if event.PublishedAt != nil {
+		publishedAt := *event.PublishedAt
+		clone.PublishedAt = &publishedAt
+	}
+
+	return &clone
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"github.com/doug-martin/goqu/v9"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewRepository returns implementation of Repository for configured db driver and its replicas, decorated by cache of Finder if it is enabled
+func NewRepository(
+	configurator configurator.Configurator,
+	config *db.Config,
+	cacheConfig *CacheConfig,
+	replicasConfig *database.ReplicasConfig,
+	sqlDatabase *goqu.Database,
+	replicas database.Replicas,
+	tracer trace.Tracer,
+	logger log.Logger,
+) Repository {
+	var repository Repository
+
+	if config.Driver == database.MemoryDriver {
+		repository = NewMemory(tracer)
+	} else {
+		repository = NewSql(sqlDatabase, replicas, replicasConfig.ReadYourWrites, tracer)
+	}
+
+	configurator.SetDefault(CacheSizeFieldName, CacheSizeDefault)
+	configurator.SetDefault(CacheTTLFieldName, CacheTTLDefault)
+
+	if size := configurator.GetInt(CacheSizeFieldName); size > 0 && cacheConfig.Size == CacheSizeDefault {
+		cacheConfig.Size = size
+	}
+
+	if ttl := configurator.GetDuration(CacheTTLFieldName); ttl > 0 && cacheConfig.TTL == CacheTTLDefault {
+		cacheConfig.TTL = ttl
+	}
+
+	if cacheConfig.TTL <= 0 {
+		cacheConfig.TTL = CacheTTLDefault
+	}
+
+	logger.Infof("repository.cache: size - %d, ttl - %s", cacheConfig.Size, cacheConfig.TTL)
+
+	if cacheConfig.Size <= 0 {
+		return repository
+	}
+
+	return NewCached(cacheConfig, repository, tracer)
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sort fields of Filter
+const (
+	IdSort      = "id"
+	LoginSort   = "login"
+	CreatedSort = "created_at"
+	// UpdatedSort sorting by time of the last change, creation time for never updated logins
+	UpdatedSort = "updated_at"
+)
+
+var sorts = map[string]bool{IdSort: true, LoginSort: true, CreatedSort: true, UpdatedSort: true}
+
+// Filter conditions and order of Paginator, zero Filter selects every login ordered by id,
+// ranges of times include the start and exclude the end
+type Filter struct {
+	Banned      *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	// LoginPrefix compared with canonical form of login
+	LoginPrefix string
+	// MetadataKey selects logins whose metadata has string MetadataValue by the key, empty key doesn't filter
+	MetadataKey   string
+	MetadataValue string
+
+	Sort string
+	Desc bool
+}
+
+// Validate checking sort field and ranges of the filter
+func (filter *Filter) Validate() error {
+	if filter.Sort != "" && !sorts[filter.Sort] {
+		return fmt.Errorf("%w: unknown sort '%s'", InvalidFilterError, filter.Sort)
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedTo.Before(*filter.CreatedFrom) {
+		return fmt.Errorf("%w: created range ends before start", InvalidFilterError)
+	}
+
+	if filter.UpdatedFrom != nil && filter.UpdatedTo != nil && filter.UpdatedTo.Before(*filter.UpdatedFrom) {
+		return fmt.Errorf("%w: updated range ends before start", InvalidFilterError)
+	}
+
+	if filter.MetadataKey != "" {
+		return checkMetadataKey(filter.MetadataKey)
+	}
+
+	return nil
+}
+
+// sort returns sort field of the filter, IdSort by default
+func (filter *Filter) sort() string {
+	if filter.Sort == "" {
+		return IdSort
+	}
+
+	return filter.Sort
+}
+
+// match reports whether login satisfies conditions of the filter, used by memory repository
+func (filter *Filter) match(login *Login) bool {
+	if filter.Banned != nil && login.Banned != *filter.Banned {
+		return false
+	}
+
+	if !inRange(login.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+		return false
+	}
+
+	if (filter.UpdatedFrom != nil || filter.UpdatedTo != nil) && !inRange(login.UpdateAt, filter.UpdatedFrom, filter.UpdatedTo) {
+		return false
+	}
+
+	if filter.MetadataKey != "" {
+		if value, ok := metadataString(login.Metadata, filter.MetadataKey); !ok || value != filter.MetadataValue {
+			return false
+		}
+	}
+
+	return strings.HasPrefix(login.LoginCanonical, Canonical(filter.LoginPrefix))
+}
+
+// less reports whether login is placed before another login in order of the filter, used by memory repository
+func (filter *Filter) less(login *Login, another *Login) bool {
+	return filter.compare(NewCursor(login, filter), NewCursor(another, filter)) < 0
+}
+
+// compare returns order of cursors of the filter: negative if cursor is placed before another, zero if equal
+func (filter *Filter) compare(cursor *Cursor, another *Cursor) int {
+	result := 0
+
+	switch filter.sort() {
+	case LoginSort:
+		result = strings.Compare(cursor.Login, another.Login)
+	case CreatedSort, UpdatedSort:
+		switch {
+		case cursor.Time.Before(*another.Time):
+			result = -1
+		case cursor.Time.After(*another.Time):
+			result = 1
+		}
+	}
+
+	if result == 0 {
+		switch {
+		case cursor.Id < another.Id:
+			result = -1
+		case cursor.Id > another.Id:
+			result = 1
+		}
+	}
+
+	if filter.Desc {
+		return -result
+	}
+
+	return result
+}
+
+// checkCursor checking that cursor was created for sort of the filter
+func (filter *Filter) checkCursor(cursor *Cursor) error {
+	sort := cursor.Sort
+	if sort == "" {
+		sort = IdSort
+	}
+
+	if sort != filter.sort() || cursor.Desc != filter.Desc {
+		return fmt.Errorf("%w: cursor was created for another sort", InvalidCursorError)
+	}
+
+	if (sort == CreatedSort || sort == UpdatedSort) && cursor.Time == nil {
+		return InvalidCursorError
+	}
+
+	return nil
+}
+
+// inRange reports whether value is inside of [from, to) range, nil value is out of any range
+func inRange(value *time.Time, from *time.Time, to *time.Time) bool {
+	if from == nil && to == nil {
+		return true
+	}
+
+	if value == nil {
+		return false
+	}
+
+	return (from == nil || !value.Before(*from)) && (to == nil || value.Before(*to))
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/time"
+	"testing"
+	stdTime "time"
+)
+
+func TestFilter_Validate(t *testing.T) {
+	now := time.NowUTC()
+	before := now.Add(-stdTime.Hour)
+
+	for name, filter := range map[string]*Filter{
+		"unknown sort":          {Sort: "uuid"},
+		"created range":         {CreatedFrom: &now, CreatedTo: &before},
+		"updated range":         {UpdatedFrom: &now, UpdatedTo: &before},
+		"metadata key of space": {MetadataKey: "a b"},
+	} {
+		if err := filter.Validate(); !errors.Is(err, InvalidFilterError) {
+			t.Errorf("%s: %v, expected InvalidFilterError", name, err)
+		}
+	}
+}
+
+func TestRepository_FilterSort(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		// logins of batch are created at the same time, so pages sorted by time are ordered by id inside of it
+		var logins []*Login
+		for _, login := range []string{"dave", "Alice", "carol", "bob", "alex"} {
+			logins = append(logins, &Login{Login: login, Banned: login[0] == 'a' || login[0] == 'A'})
+		}
+
+		if _, err := repository.InsertBatch(ctx, logins); err != nil {
+			t.Fatal(err)
+		}
+
+		banned := true
+
+		for name, test := range map[string]struct {
+			filter   *Filter
+			expected string
+		}{
+			"login":             {&Filter{Sort: LoginSort}, "[alex Alice bob carol dave]"},
+			"login desc":        {&Filter{Sort: LoginSort, Desc: true}, "[dave carol bob Alice alex]"},
+			"created":           {&Filter{Sort: CreatedSort}, "[dave Alice carol bob alex]"},
+			"updated desc":      {&Filter{Sort: UpdatedSort, Desc: true}, "[alex bob carol Alice dave]"},
+			"banned":            {&Filter{Banned: &banned, Sort: LoginSort}, "[alex Alice]"},
+			"prefix":            {&Filter{LoginPrefix: "A", Sort: LoginSort, Desc: true}, "[Alice alex]"},
+			"prefix and banned": {&Filter{LoginPrefix: "d", Banned: &banned}, "[]"},
+		} {
+			if actual := walk(t, ctx, repository, test.filter, 2, nil); fmt.Sprint(actual) != test.expected {
+				t.Errorf("%s: walked %v, expected %s", name, actual, test.expected)
+			}
+		}
+
+		byLogin := &Filter{Sort: LoginSort}
+		cursor := NewCursor(logins[0], byLogin)
+
+		for name, filter := range map[string]*Filter{
+			"another sort":      {Sort: CreatedSort},
+			"another direction": {Sort: LoginSort, Desc: true},
+		} {
+			if _, err := repository.PageByCursor(ctx, filter, cursor, 2); !errors.Is(err, InvalidCursorError) {
+				t.Errorf("page of cursor of %s: %v, expected InvalidCursorError", name, err)
+			}
+		}
+	})
+}
+
+func TestRepository_FilterMetadata(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		for login, metadata := range map[string]string{
+			"alice": `{"tier":"gold","nested":{"tier":"silver"}}`,
+			"bob":   `{"tier":"silver"}`,
+			"carol": `{"tier":1}`,
+			"dave":  `{"tier":"1"}`,
+			"eve":   ``,
+		} {
+			if _, err := repository.Insert(ctx, &Login{Login: login, Metadata: metadata}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for name, test := range map[string]struct {
+			filter   *Filter
+			expected string
+		}{
+			"string value":         {&Filter{MetadataKey: "tier", MetadataValue: "gold", Sort: LoginSort}, "[alice]"},
+			"nested isn't matched": {&Filter{MetadataKey: "tier", MetadataValue: "silver", Sort: LoginSort}, "[bob]"},
+			"number isn't string":  {&Filter{MetadataKey: "tier", MetadataValue: "1", Sort: LoginSort}, "[dave]"},
+			"missing key":          {&Filter{MetadataKey: "plan", Sort: LoginSort}, "[]"},
+			"metadata and prefix":  {&Filter{MetadataKey: "tier", MetadataValue: "silver", LoginPrefix: "a"}, "[]"},
+		} {
+			if actual := walk(t, ctx, repository, test.filter, 2, nil); fmt.Sprint(actual) != test.expected {
+				t.Errorf("%s: walked %v, expected %s", name, actual, test.expected)
+			}
+		}
+	})
+}
@@ -0,0 +1,585 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"sort"
+	"sync"
+	stdTime "time"
+)
+
+// locker guarding memoryState, sync.RWMutex or memoryTx inside of transaction
+type locker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+}
+
+// memory thread-safe implementation of Repository, keeps the same semantics as sql
+type memory struct {
+	mutex  locker
+	tracer trace.Tracer
+
+	*memoryState
+}
+
+// memoryState data of memory, shared by memory and its transactions
+type memoryState struct {
+	lastId  int64
+	logins  []*Login
+	byUuid  map[uuid.UUID]*Login
+	byLogin map[string]*Login // by memoryLoginKey
+
+	lastBanId int64
+	bans      []*BanRecord
+
+	lastReservedId int64
+	reservations   []*Reserved
+
+	lastAliasId int64
+	aliases     []*Alias
+
+	lastEventId int64
+	events      []*Event
+	cursors     map[string]int64 // sequences of the last events handled by consumers
+
+	lastWebhookId int64
+	webhooks      []*Webhook
+
+	lastDeliveryId int64
+	deliveries     []*Delivery
+
+	lastRevision int64
+}
+
+// memoryLoginKey returns key of login in memoryState.byLogin, canonical login is unique inside of tenant
+func memoryLoginKey(tenant string, canonical string) string {
+	return tenant + "/" + canonical
+}
+
+func NewMemory(tracer trace.Tracer) Repository {
+	return &memory{
+		mutex:  &sync.RWMutex{},
+		tracer: tracer,
+		memoryState: &memoryState{
+			byUuid:  map[uuid.UUID]*Login{},
+			byLogin: map[string]*Login{},
+			cursors: map[string]int64{},
+		},
+	}
+}
+
+func (repository *memory) FindByUuid(ctx context.Context, uuid uuid.UUID) (*Login, error) {
+	_, span := repository.tracer.Start(ctx, "FindByUuid")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	login, ok := repository.byTenantUuid(ctx, uuid)
+	if !ok {
+		return nil, db.RecordNotFoundError
+	}
+
+	return login.clone(), nil
+}
+
+// byTenantUuid returns login by uuid if it belongs to tenant of ctx, must be called under lock
+func (repository *memory) byTenantUuid(ctx context.Context, uuid uuid.UUID) (*Login, bool) {
+	login, ok := repository.byUuid[uuid]
+	if !ok || login.Tenant != TenantFrom(ctx) {
+		return nil, false
+	}
+
+	return login, true
+}
+
+func (repository *memory) FindByLogin(ctx context.Context, login string) (*Login, error) {
+	_, span := repository.tracer.Start(ctx, "FindByLogin")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("login", login),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	model, ok := repository.byLogin[memoryLoginKey(TenantFrom(ctx), Canonical(login))]
+	if !ok {
+		return nil, db.RecordNotFoundError
+	}
+
+	return model.clone(), nil
+}
+
+func (repository *memory) BanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error) {
+	_, span := repository.tracer.Start(ctx, "BanByUuid")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("reason", ban.Reason),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	login, ok := repository.byTenantUuid(ctx, uuid)
+	if !ok {
+		return false, db.RecordNotFoundError
+	}
+
+	if ban.Version > 0 && login.Version != ban.Version {
+		return false, VersionConflictError
+	}
+
+	if ban.applied(login) {
+		return true, nil
+	}
+
+	now := time.NowUTC()
+	login.Banned = true
+	login.BannedUntil = nil
+	login.BanReason = ban.Reason
+	login.Version++
+	login.Revision = repository.nextRevision()
+	login.UpdateAt = &now
+
+	if ban.Until != nil {
+		until := ban.Until.In(stdTime.UTC)
+		login.BannedUntil = &until
+	}
+
+	repository.appendBans(true, ban, login.Uuid)
+
+	if err := repository.appendEvents(LoginBannedEvent, login); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UnbanByUuid lifting ban of login and returns the ban state, not banned login is left untouched
+func (repository *memory) UnbanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error) {
+	_, span := repository.tracer.Start(ctx, "UnbanByUuid")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("reason", ban.Reason),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	login, ok := repository.byTenantUuid(ctx, uuid)
+	if !ok {
+		return false, db.RecordNotFoundError
+	}
+
+	if ban.Version > 0 && login.Version != ban.Version {
+		return false, VersionConflictError
+	}
+
+	if login.Banned {
+		login.unban(time.NowUTC())
+		login.Revision = repository.nextRevision()
+		repository.appendBans(false, ban, login.Uuid)
+
+		if err := repository.appendEvents(LoginUnbannedEvent, login); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func (repository *memory) UnbanExpired(ctx context.Context, now stdTime.Time) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "UnbanExpired")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("now", now.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	var uuids []uuid.UUID
+	var unbanned []*Login
+
+	for _, login := range repository.logins {
+		if login.Banned && login.BannedUntil != nil && !login.BannedUntil.After(now) {
+			login.unban(now)
+			login.Revision = repository.nextRevision()
+			uuids = append(uuids, login.Uuid)
+			unbanned = append(unbanned, login)
+		}
+	}
+
+	repository.appendBans(false, &Ban{Actor: ExpirerActor, Reason: ExpiredReason}, uuids...)
+
+	if err := repository.appendEvents(LoginUnbannedEvent, unbanned...); err != nil {
+		return 0, err
+	}
+
+	return int64(len(uuids)), nil
+}
+
+// filter returns logins matched by the filter in its order, must be called under read lock
+func (repository *memory) filter(ctx context.Context, filter *Filter) []*Login {
+	tenant := TenantFrom(ctx)
+
+	var logins []*Login
+
+	for _, login := range repository.logins {
+		if login.Tenant == tenant && filter.match(login) {
+			logins = append(logins, login)
+		}
+	}
+
+	sort.SliceStable(logins, func(i, j int) bool {
+		return filter.less(logins[i], logins[j])
+	})
+
+	return logins
+}
+
+func (repository *memory) Count(ctx context.Context, filter *Filter) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "Count")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return int64(len(repository.filter(ctx, filter))), nil
+}
+
+func (repository *memory) Page(ctx context.Context, filter *Filter, page uint, limit uint) ([]*Login, error) {
+	_, span := repository.tracer.Start(ctx, "Page")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	matched := repository.filter(ctx, filter)
+
+	offset := int(page * limit)
+	if offset >= len(matched) || limit == 0 {
+		return nil, io.EOF
+	}
+
+	end := offset + int(limit)
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	logins := make([]*Login, 0, end-offset)
+	for _, login := range matched[offset:end] {
+		logins = append(logins, login.clone())
+	}
+
+	return logins, nil
+}
+
+func (repository *memory) PageByCursor(ctx context.Context, filter *Filter, cursor *Cursor, limit uint) ([]*Login, error) {
+	_, span := repository.tracer.Start(ctx, "PageByCursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("cursor.id", cursor.Id),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := filter.checkCursor(cursor); err != nil {
+		return nil, err
+	}
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var logins []*Login
+
+	for _, login := range repository.filter(ctx, filter) {
+		if uint(len(logins)) >= limit {
+			break
+		}
+
+		if filter.compare(NewCursor(login, filter), cursor) > 0 {
+			logins = append(logins, login.clone())
+		}
+	}
+
+	if len(logins) == 0 {
+		return nil, io.EOF
+	}
+
+	return logins, nil
+}
+
+func (repository *memory) Update(ctx context.Context, login *Login) (*Login, error) {
+	_, span := repository.tracer.Start(ctx, "Update")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", login.Uuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	stored, ok := repository.byTenantUuid(ctx, login.Uuid)
+	if !ok {
+		return nil, db.RecordNotFoundError
+	}
+
+	if stored.Version != login.Version {
+		return nil, VersionConflictError
+	}
+
+	login.LoginCanonical = Canonical(login.Login)
+
+	login.Tenant = stored.Tenant
+	defaultMetadata(login)
+
+	if owner, ok := repository.byLogin[memoryLoginKey(login.Tenant, login.LoginCanonical)]; ok && owner != stored {
+		return nil, ErrDuplicateLogin
+	}
+
+	now := time.NowUTC()
+	login.UpdateAt = &now
+	login.Version++
+	login.Revision = repository.nextRevision()
+
+	delete(repository.byLogin, memoryLoginKey(stored.Tenant, stored.LoginCanonical))
+
+	if stored.LoginCanonical != login.LoginCanonical {
+		repository.appendAlias(&Alias{
+			LoginUuid:      stored.Uuid,
+			Tenant:         stored.Tenant,
+			Login:          stored.Login,
+			LoginCanonical: stored.LoginCanonical,
+			CreatedAt:      &now,
+		})
+	}
+
+	previous := stored.clone()
+
+	id := stored.Id
+	*stored = *login.clone()
+	stored.Id = id
+
+	repository.byLogin[memoryLoginKey(stored.Tenant, stored.LoginCanonical)] = stored
+
+	if previous.Banned != stored.Banned {
+		repository.appendBans(stored.Banned, &Ban{Until: stored.BannedUntil, Reason: stored.BanReason}, stored.Uuid)
+	}
+
+	if err := repository.appendEvents(updatedEvent(previous, stored), stored); err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+func (repository *memory) Insert(ctx context.Context, login *Login) (*Login, error) {
+	_, span := repository.tracer.Start(ctx, "Insert")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("login", login.Login),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	login.Uuid = uuid.New()
+	login.Tenant = TenantFrom(ctx)
+	login.LoginCanonical = Canonical(login.Login)
+	defaultMetadata(login)
+	login.Version = 1
+
+	now := time.NowUTC()
+	login.CreatedAt = &now
+
+	if _, ok := repository.byUuid[login.Uuid]; ok {
+		return nil, ErrDuplicateUuid
+	}
+
+	if _, ok := repository.byLogin[memoryLoginKey(login.Tenant, login.LoginCanonical)]; ok {
+		return nil, ErrDuplicateLogin
+	}
+
+	login.Revision = repository.nextRevision()
+
+	repository.lastId++
+
+	stored := login.clone()
+	stored.Id = repository.lastId
+
+	repository.logins = append(repository.logins, stored)
+	repository.byUuid[stored.Uuid] = stored
+	repository.byLogin[memoryLoginKey(stored.Tenant, stored.LoginCanonical)] = stored
+
+	if stored.Banned {
+		repository.appendBans(true, &Ban{Until: stored.BannedUntil, Reason: stored.BanReason}, stored.Uuid)
+	}
+
+	if err := repository.appendEvents(LoginCreatedEvent, stored); err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+// appendBans appending history records of ban or unban of logins, must be called under write lock
+func (repository *memory) appendBans(banned bool, ban *Ban, uuids ...uuid.UUID) {
+	now := time.NowUTC()
+
+	for _, loginUuid := range uuids {
+		repository.lastBanId++
+
+		record := newBanRecord(loginUuid, banned, ban, now)
+		record.Id = repository.lastBanId
+
+		repository.bans = append(repository.bans, record)
+	}
+}
+
+func (repository *memory) CountBans(ctx context.Context, uuid uuid.UUID) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "CountBans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	count := int64(0)
+
+	for _, record := range repository.bans {
+		if record.LoginUuid == uuid {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (repository *memory) PageBans(ctx context.Context, uuid uuid.UUID, page uint, limit uint) ([]*BanRecord, error) {
+	_, span := repository.tracer.Start(ctx, "PageBans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	offset := page * limit
+
+	var records []*BanRecord
+
+	for _, record := range repository.bans {
+		if uint(len(records)) >= limit {
+			break
+		}
+
+		if record.LoginUuid != uuid {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+
+		records = append(records, record.clone())
+	}
+
+	if len(records) == 0 {
+		return nil, io.EOF
+	}
+
+	return records, nil
+}
+
+func (repository *memory) PageBansByCursor(ctx context.Context, uuid uuid.UUID, cursor *Cursor, limit uint) ([]*BanRecord, error) {
+	_, span := repository.tracer.Start(ctx, "PageBansByCursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.Int64("cursor.id", cursor.Id),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var records []*BanRecord
+
+	for _, record := range repository.bans {
+		if uint(len(records)) >= limit {
+			break
+		}
+
+		if record.LoginUuid == uuid && record.Id > cursor.Id {
+			records = append(records, record.clone())
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, io.EOF
+	}
+
+	return records, nil
+}
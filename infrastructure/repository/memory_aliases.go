@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"github.com/diez37/go-packages/clients/db"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// appendAlias appending former login of renamed login, must be called under write lock
+func (repository *memory) appendAlias(alias *Alias) {
+	repository.lastAliasId++
+
+	stored := alias.clone()
+	stored.Id = repository.lastAliasId
+
+	repository.aliases = append(repository.aliases, stored)
+}
+
+func (repository *memory) FindAlias(ctx context.Context, login string) (*Alias, error) {
+	_, span := repository.tracer.Start(ctx, "FindAlias")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("login", login),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenant, canonical := TenantFrom(ctx), Canonical(login)
+
+	for index := len(repository.aliases) - 1; index >= 0; index-- {
+		if alias := repository.aliases[index]; alias.Tenant == tenant && alias.LoginCanonical == canonical {
+			return repository.aliases[index].clone(), nil
+		}
+	}
+
+	return nil, db.RecordNotFoundError
+}
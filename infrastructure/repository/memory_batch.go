@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (repository *memory) InsertBatch(ctx context.Context, logins []*Login) ([]error, error) {
+	_, span := repository.tracer.Start(ctx, "InsertBatch")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("count", len(logins)),
+		attribute.String("repository", "memory"),
+	)
+
+	errs, _ := newBatch(logins)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	now := time.NowUTC()
+	tenant := TenantFrom(ctx)
+
+	for index, login := range logins {
+		if errs[index] != nil {
+			continue
+		}
+
+		if _, ok := repository.byLogin[memoryLoginKey(tenant, login.LoginCanonical)]; ok {
+			errs[index] = ErrDuplicateLogin
+			continue
+		}
+
+		login.Uuid = uuid.New()
+		login.Tenant = tenant
+		defaultMetadata(login)
+		login.Version = 1
+		login.Revision = repository.nextRevision()
+		login.CreatedAt = &now
+
+		repository.lastId++
+
+		stored := login.clone()
+		stored.Id = repository.lastId
+
+		repository.logins = append(repository.logins, stored)
+		repository.byUuid[stored.Uuid] = stored
+		repository.byLogin[memoryLoginKey(stored.Tenant, stored.LoginCanonical)] = stored
+
+		if stored.Banned {
+			repository.appendBans(true, &Ban{Until: stored.BannedUntil, Reason: stored.BanReason}, stored.Uuid)
+		}
+
+		if err := repository.appendEvents(LoginCreatedEvent, stored); err != nil {
+			return nil, err
+		}
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (repository *memory) Export(ctx context.Context, filter *Filter, fn func(login *Login) error) error {
+	_, span := repository.tracer.Start(ctx, "Export")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	repository.mutex.RLock()
+
+	matched := repository.filter(ctx, filter)
+
+	logins := make([]*Login, len(matched))
+	for index, login := range matched {
+		logins[index] = login.clone()
+	}
+
+	repository.mutex.RUnlock()
+
+	for _, login := range logins {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(login); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
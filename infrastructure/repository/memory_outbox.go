@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// appendEvents appending events of logins to outbox, must be called under write lock
+func (repository *memory) appendEvents(eventType string, logins ...*Login) error {
+	now := time.NowUTC()
+
+	for _, login := range logins {
+		event, err := newEvent(eventType, login, now)
+		if err != nil {
+			return err
+		}
+
+		repository.lastEventId++
+		event.Id = repository.lastEventId
+		// writes are serialized by the lock, so ids are in order of commits
+		event.Sequence = event.Id
+
+		repository.events = append(repository.events, event)
+	}
+
+	return nil
+}
+
+func (repository *memory) Unpublished(ctx context.Context, limit uint) ([]*Event, error) {
+	_, span := repository.tracer.Start(ctx, "Unpublished")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var events []*Event
+
+	for _, event := range repository.events {
+		if uint(len(events)) == limit {
+			break
+		}
+
+		if event.PublishedAt == nil {
+			events = append(events, event.clone())
+		}
+	}
+
+	return events, nil
+}
+
+func (repository *memory) MarkPublished(ctx context.Context, ids ...int64) error {
+	_, span := repository.tracer.Start(ctx, "MarkPublished")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("count", len(ids)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	published := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		published[id] = true
+	}
+
+	now := time.NowUTC()
+
+	for _, event := range repository.events {
+		if published[event.Id] && event.PublishedAt == nil {
+			publishedAt := now
+			event.PublishedAt = &publishedAt
+		}
+	}
+
+	return nil
+}
+
+func (repository *memory) EventsAfter(ctx context.Context, sequence int64, limit uint) ([]*Event, error) {
+	_, span := repository.tracer.Start(ctx, "EventsAfter")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("sequence", sequence),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenant := TenantFrom(ctx)
+
+	var events []*Event
+
+	for _, event := range repository.events {
+		if uint(len(events)) == limit {
+			break
+		}
+
+		if event.Tenant == tenant && event.Sequence > sequence {
+			events = append(events, event.clone())
+		}
+	}
+
+	return events, nil
+}
+
+func (repository *memory) LastSequence(ctx context.Context) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "LastSequence")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return repository.lastEventId, nil
+}
+
+func (repository *memory) AllEventsAfter(ctx context.Context, sequence int64, limit uint) ([]*Event, error) {
+	_, span := repository.tracer.Start(ctx, "AllEventsAfter")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("sequence", sequence),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var events []*Event
+
+	for _, event := range repository.events {
+		if uint(len(events)) == limit {
+			break
+		}
+
+		if event.Sequence > sequence {
+			events = append(events, event.clone())
+		}
+	}
+
+	return events, nil
+}
+
+func (repository *memory) Cursor(ctx context.Context, consumer string) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "Cursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("consumer", consumer),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return repository.cursors[consumer], nil
+}
+
+func (repository *memory) MoveCursor(ctx context.Context, consumer string, sequence int64) error {
+	_, span := repository.tracer.Start(ctx, "MoveCursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("consumer", consumer),
+		attribute.Int64("sequence", sequence),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.cursors[consumer] = sequence
+
+	return nil
+}
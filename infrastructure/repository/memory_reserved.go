@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (repository *memory) MatchReserved(ctx context.Context, login string) (*Reserved, error) {
+	_, span := repository.tracer.Start(ctx, "MatchReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("login", login),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	canonical := Canonical(login)
+
+	for _, reserved := range repository.reservations {
+		if reserved.Match(canonical) {
+			return reserved.clone(), nil
+		}
+	}
+
+	return nil, db.RecordNotFoundError
+}
+
+func (repository *memory) ListReserved(ctx context.Context) ([]*Reserved, error) {
+	_, span := repository.tracer.Start(ctx, "ListReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var reservations []*Reserved
+
+	for _, reserved := range repository.reservations {
+		reservations = append(reservations, reserved.clone())
+	}
+
+	return reservations, nil
+}
+
+func (repository *memory) AddReserved(ctx context.Context, reserved *Reserved) (*Reserved, error) {
+	_, span := repository.tracer.Start(ctx, "AddReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("pattern", reserved.Pattern),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, stored := range repository.reservations {
+		if stored.Pattern == reserved.Pattern {
+			return nil, ErrDuplicateReserved
+		}
+	}
+
+	now := time.NowUTC()
+	reserved.CreatedAt = &now
+
+	repository.lastReservedId++
+
+	stored := reserved.clone()
+	stored.Id = repository.lastReservedId
+
+	repository.reservations = append(repository.reservations, stored)
+
+	return reserved, nil
+}
+
+func (repository *memory) RemoveReserved(ctx context.Context, pattern string) error {
+	_, span := repository.tracer.Start(ctx, "RemoveReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("pattern", pattern),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	canonical := Canonical(pattern)
+
+	for index, reserved := range repository.reservations {
+		if reserved.Pattern == canonical {
+			repository.reservations = append(repository.reservations[:index], repository.reservations[index+1:]...)
+
+			return nil
+		}
+	}
+
+	return db.RecordNotFoundError
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"sort"
+)
+
+// nextRevision returns the next revision of writes of logins, must be called under write lock
+func (repository *memory) nextRevision() int64 {
+	repository.lastRevision++
+
+	return repository.lastRevision
+}
+
+func (repository *memory) Revision(ctx context.Context) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "Revision")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return repository.lastRevision, nil
+}
+
+func (repository *memory) ChangedSince(ctx context.Context, since int64, until int64, limit uint) ([]*Login, error) {
+	_, span := repository.tracer.Start(ctx, "ChangedSince")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("since", since),
+		attribute.Int64("until", until),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenant := TenantFrom(ctx)
+
+	var logins []*Login
+
+	for _, login := range repository.logins {
+		if login.Tenant == tenant && login.Revision > since && login.Revision <= until {
+			logins = append(logins, login)
+		}
+	}
+
+	sort.Slice(logins, func(i, j int) bool {
+		return logins[i].Revision < logins[j].Revision
+	})
+
+	if uint(len(logins)) > limit {
+		logins = logins[:limit]
+	}
+
+	if len(logins) == 0 {
+		return nil, io.EOF
+	}
+
+	changed := make([]*Login, len(logins))
+	for index, login := range logins {
+		changed[index] = login.clone()
+	}
+
+	return changed, nil
+}
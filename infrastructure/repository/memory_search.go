@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"sort"
+	"unicode/utf8"
+)
+
+// search returns logins matched by the search in order of Searcher, must be called under read lock
+func (repository *memory) search(ctx context.Context, search *Search) []*Login {
+	tenant := TenantFrom(ctx)
+
+	var logins []*Login
+	ranks := map[*Login]int{}
+
+	for _, login := range repository.logins {
+		if login.Tenant != tenant {
+			continue
+		}
+
+		if rank := search.rank(login.LoginCanonical); rank != noSearchRank {
+			logins = append(logins, login)
+			ranks[login] = rank
+		}
+	}
+
+	sort.SliceStable(logins, func(i, j int) bool {
+		if ranks[logins[i]] != ranks[logins[j]] {
+			return ranks[logins[i]] < ranks[logins[j]]
+		}
+
+		left, right := utf8.RuneCountInString(logins[i].LoginCanonical), utf8.RuneCountInString(logins[j].LoginCanonical)
+		if left != right {
+			return left < right
+		}
+
+		return logins[i].Id < logins[j].Id
+	})
+
+	return logins
+}
+
+func (repository *memory) CountSearch(ctx context.Context, search *Search) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "CountSearch")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("query", search.Query),
+		attribute.String("mode", search.Mode),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return int64(len(repository.search(ctx, search))), nil
+}
+
+func (repository *memory) Search(ctx context.Context, search *Search, page uint, limit uint) ([]*Login, error) {
+	_, span := repository.tracer.Start(ctx, "Search")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("query", search.Query),
+		attribute.String("mode", search.Mode),
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	matched := repository.search(ctx, search)
+
+	offset := int(page * limit)
+	if offset >= len(matched) || limit == 0 {
+		return nil, io.EOF
+	}
+
+	end := offset + int(limit)
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	logins := make([]*Login, 0, end-offset)
+	for _, login := range matched[offset:end] {
+		logins = append(logins, login.clone())
+	}
+
+	return logins, nil
+}
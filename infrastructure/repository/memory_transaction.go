@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// memoryTx locker of memory scoped by transaction, the lock of memory is already held by the transaction,
+// state is backed up on the first write so the transaction can be rolled back
+type memoryTx struct {
+	state  *memoryState
+	backup *memoryState
+}
+
+func (tx *memoryTx) Lock() {
+	if tx.backup == nil {
+		tx.backup = tx.state.clone()
+	}
+}
+
+func (tx *memoryTx) Unlock() {}
+
+func (tx *memoryTx) RLock() {}
+
+func (tx *memoryTx) RUnlock() {}
+
+func (repository *memory) Transaction(ctx context.Context, fn func(repository Repository) error) error {
+	ctx, span := repository.tracer.Start(ctx, "Transaction")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	return repository.transaction(fn)
+}
+
+func (repository *memory) Snapshot(ctx context.Context, fn func(repository Repository) error) error {
+	ctx, span := repository.tracer.Start(ctx, "Snapshot")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	return repository.transaction(fn)
+}
+
+// transaction running fn with repository holding the lock until fn returns, state is restored if fn fails or panics,
+// joins the current transaction if exists
+func (repository *memory) transaction(fn func(repository Repository) error) error {
+	if _, ok := repository.mutex.(*memoryTx); ok {
+		return fn(repository)
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	tx := &memoryTx{state: repository.memoryState}
+
+	rollback := func() {
+		if tx.backup != nil {
+			*repository.memoryState = *tx.backup
+		}
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	err := fn(&memory{mutex: tx, tracer: repository.tracer, memoryState: repository.memoryState})
+	if err != nil {
+		rollback()
+	}
+
+	return err
+}
+
+// clone returns deep copy of state
+func (state *memoryState) clone() *memoryState {
+	clone := &memoryState{
+		lastId:         state.lastId,
+		logins:         make([]*Login, len(state.logins)),
+		byUuid:         make(map[uuid.UUID]*Login, len(state.byUuid)),
+		byLogin:        make(map[string]*Login, len(state.byLogin)),
+		lastBanId:      state.lastBanId,
+		bans:           make([]*BanRecord, len(state.bans)),
+		lastReservedId: state.lastReservedId,
+		reservations:   make([]*Reserved, len(state.reservations)),
+		lastAliasId:    state.lastAliasId,
+		aliases:        make([]*Alias, len(state.aliases)),
+		lastEventId:    state.lastEventId,
+		events:         make([]*Event, len(state.events)),
+		cursors:        make(map[string]int64, len(state.cursors)),
+		lastWebhookId:  state.lastWebhookId,
+		webhooks:       make([]*Webhook, len(state.webhooks)),
+		lastDeliveryId: state.lastDeliveryId,
+		deliveries:     make([]*Delivery, len(state.deliveries)),
+		lastRevision:   state.lastRevision,
+	}
+
+	for index, login := range state.logins {
+		clone.logins[index] = login.clone()
+		clone.byUuid[login.Uuid] = clone.logins[index]
+		clone.byLogin[memoryLoginKey(login.Tenant, login.LoginCanonical)] = clone.logins[index]
+	}
+
+	for index, record := range state.bans {
+		clone.bans[index] = record.clone()
+	}
+
+	for index, reserved := range state.reservations {
+		clone.reservations[index] = reserved.clone()
+	}
+
+	for index, alias := range state.aliases {
+		clone.aliases[index] = alias.clone()
+	}
+
+	for index, event := range state.events {
+		clone.events[index] = event.clone()
+	}
+
+	for consumer, sequence := range state.cursors {
+		clone.cursors[consumer] = sequence
+	}
+
+	for index, webhook := range state.webhooks {
+		clone.webhooks[index] = webhook.clone()
+	}
+
+	for index, delivery := range state.deliveries {
+		clone.deliveries[index] = delivery.clone()
+	}
+
+	return clone
+}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	stdTime "time"
+)
+
+func (repository *memory) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	_, span := repository.tracer.Start(ctx, "ListWebhooks")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	tenant := TenantFrom(ctx)
+
+	return repository.webhooksOf(func(webhook *Webhook) bool {
+		return webhook.Tenant == tenant
+	}), nil
+}
+
+func (repository *memory) AllWebhooks(ctx context.Context) ([]*Webhook, error) {
+	_, span := repository.tracer.Start(ctx, "AllWebhooks")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "memory"),
+	)
+
+	return repository.webhooksOf(func(webhook *Webhook) bool {
+		return true
+	}), nil
+}
+
+// webhooksOf returns copies of webhooks matching match in order of their ids
+func (repository *memory) webhooksOf(match func(webhook *Webhook) bool) []*Webhook {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var webhooks []*Webhook
+
+	for _, webhook := range repository.webhooks {
+		if match(webhook) {
+			webhooks = append(webhooks, webhook.clone())
+		}
+	}
+
+	return webhooks
+}
+
+func (repository *memory) FindWebhook(ctx context.Context, uuid uuid.UUID) (*Webhook, error) {
+	_, span := repository.tracer.Start(ctx, "FindWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenant := TenantFrom(ctx)
+
+	for _, webhook := range repository.webhooks {
+		if webhook.Tenant == tenant && webhook.Uuid == uuid {
+			return webhook.clone(), nil
+		}
+	}
+
+	return nil, db.RecordNotFoundError
+}
+
+func (repository *memory) AddWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	_, span := repository.tracer.Start(ctx, "AddWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("url", webhook.Url),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	webhook.Uuid = uuid.New()
+	webhook.Tenant = TenantFrom(ctx)
+
+	now := time.NowUTC()
+	webhook.CreatedAt = &now
+	webhook.UpdateAt = &now
+
+	repository.lastWebhookId++
+
+	stored := webhook.clone()
+	stored.Id = repository.lastWebhookId
+
+	repository.webhooks = append(repository.webhooks, stored)
+
+	return webhook, nil
+}
+
+func (repository *memory) UpdateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	_, span := repository.tracer.Start(ctx, "UpdateWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", webhook.Uuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	tenant := TenantFrom(ctx)
+
+	for _, stored := range repository.webhooks {
+		if stored.Tenant != tenant || stored.Uuid != webhook.Uuid {
+			continue
+		}
+
+		now := time.NowUTC()
+
+		stored.Url = webhook.Url
+		stored.Secret = webhook.Secret
+		stored.Events = webhook.Events
+		stored.Active = webhook.Active
+		stored.UpdateAt = &now
+
+		return stored.clone(), nil
+	}
+
+	return nil, db.RecordNotFoundError
+}
+
+func (repository *memory) RemoveWebhook(ctx context.Context, uuid uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx, "RemoveWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	tenant := TenantFrom(ctx)
+
+	for index, webhook := range repository.webhooks {
+		if webhook.Tenant != tenant || webhook.Uuid != uuid {
+			continue
+		}
+
+		repository.webhooks = append(repository.webhooks[:index], repository.webhooks[index+1:]...)
+
+		deliveries := repository.deliveries[:0]
+		for _, delivery := range repository.deliveries {
+			if delivery.WebhookUuid != uuid {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+
+		repository.deliveries = deliveries
+
+		return nil
+	}
+
+	return db.RecordNotFoundError
+}
+
+func (repository *memory) AddDeliveries(ctx context.Context, deliveries ...*Delivery) error {
+	_, span := repository.tracer.Start(ctx, "AddDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("count", len(deliveries)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	now := time.NowUTC()
+
+	for _, delivery := range deliveries {
+		delivery.CreatedAt = &now
+		delivery.UpdateAt = &now
+
+		repository.lastDeliveryId++
+
+		stored := delivery.clone()
+		stored.Id = repository.lastDeliveryId
+
+		repository.deliveries = append(repository.deliveries, stored)
+	}
+
+	return nil
+}
+
+func (repository *memory) DueDeliveries(ctx context.Context, now stdTime.Time, limit uint) ([]*Delivery, error) {
+	_, span := repository.tracer.Start(ctx, "DueDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("now", now.String()),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	var deliveries []*Delivery
+
+	for _, delivery := range repository.deliveries {
+		if uint(len(deliveries)) == limit {
+			break
+		}
+
+		if delivery.Status == PendingDelivery && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery.clone())
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (repository *memory) ClaimDelivery(ctx context.Context, delivery *Delivery, now stdTime.Time, until stdTime.Time) (bool, error) {
+	_, span := repository.tracer.Start(ctx, "ClaimDelivery")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("id", delivery.Id),
+		attribute.String("until", until.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	for _, stored := range repository.deliveries {
+		if stored.Id != delivery.Id {
+			continue
+		}
+
+		if stored.Status != PendingDelivery || stored.NextAttemptAt == nil || stored.NextAttemptAt.After(now) {
+			return false, nil
+		}
+
+		claimed := until
+		stored.NextAttemptAt = &claimed
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (repository *memory) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	_, span := repository.tracer.Start(ctx, "UpdateDelivery")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("id", delivery.Id),
+		attribute.String("status", delivery.Status),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	now := time.NowUTC()
+	delivery.UpdateAt = &now
+
+	for index, stored := range repository.deliveries {
+		if stored.Id == delivery.Id {
+			repository.deliveries[index] = delivery.clone()
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (repository *memory) CountDeliveries(ctx context.Context, webhookUuid uuid.UUID) (int64, error) {
+	_, span := repository.tracer.Start(ctx, "CountDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", webhookUuid.String()),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	count := int64(0)
+
+	for _, delivery := range repository.deliveries {
+		if delivery.WebhookUuid == webhookUuid {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (repository *memory) PageDeliveries(ctx context.Context, webhookUuid uuid.UUID, page uint, limit uint) ([]*Delivery, error) {
+	_, span := repository.tracer.Start(ctx, "PageDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", webhookUuid.String()),
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "memory"),
+	)
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	offset := page * limit
+
+	var deliveries []*Delivery
+
+	for index := len(repository.deliveries) - 1; index >= 0; index-- {
+		if uint(len(deliveries)) >= limit {
+			break
+		}
+
+		delivery := repository.deliveries[index]
+		if delivery.WebhookUuid != webhookUuid {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+
+		deliveries = append(deliveries, delivery.clone())
+	}
+
+	if len(deliveries) == 0 {
+		return nil, io.EOF
+	}
+
+	return deliveries, nil
+}
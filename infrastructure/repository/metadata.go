@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// EmptyMetadata metadata of login without attributes
+const EmptyMetadata = "{}"
+
+// metadataKeyPattern keys of metadata available for Filter
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// defaultMetadata setting EmptyMetadata to logins without metadata
+func defaultMetadata(logins ...*Login) {
+	for _, login := range logins {
+		if login.Metadata == "" {
+			login.Metadata = EmptyMetadata
+		}
+	}
+}
+
+// checkMetadataKey returns InvalidFilterError if key can't be filtered by
+func checkMetadataKey(key string) error {
+	if !metadataKeyPattern.MatchString(key) {
+		return fmt.Errorf("%w: metadata key '%s' must be up to 64 letters, digits, '-' and '_'", InvalidFilterError, key)
+	}
+
+	return nil
+}
+
+// metadataPath returns json path of key of metadata
+func metadataPath(key string) string {
+	return fmt.Sprintf(`$."%s"`, key)
+}
+
+// RawMetadata returns metadata of login as json, EmptyMetadata if login has no metadata
+func RawMetadata(login *Login) json.RawMessage {
+	if login.Metadata == "" {
+		return json.RawMessage(EmptyMetadata)
+	}
+
+	return json.RawMessage(login.Metadata)
+}
+
+// metadataString returns string value of key of metadata, false if metadata has no string value of key
+func metadataString(metadata string, key string) (string, bool) {
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(metadata), &attributes); err != nil {
+		return "", false
+	}
+
+	value, ok := attributes[key].(string)
+
+	return value, ok
+}
@@ -1,15 +1,202 @@
 package repository
 
 import (
+	"fmt"
 	"github.com/google/uuid"
+	"path"
+	"strings"
 	"time"
 )
 
 type Login struct {
+	Id             int64      `db:"-"`
+	Uuid           uuid.UUID  `db:"uuid"`
+	Tenant         string     `db:"tenant"`
+	Login          string     `db:"login"`
+	LoginCanonical string     `db:"login_canonical"`
+	Banned         bool       `db:"banned"`
+	BannedUntil    *time.Time `db:"banned_until"`
+	BanReason      string     `db:"ban_reason"`
+	Version        int64      `db:"version"`
+	Revision       int64      `db:"revision"` // global revision of the last write of login
+	Metadata       string     `db:"metadata"` // json object of arbitrary attributes
+	CreatedAt      *time.Time `db:"created_at"`
+	UpdateAt       *time.Time `db:"update_at"`
+}
+
+const (
+	// ExpirerActor actor of unbans made by expiration of timed bans
+	ExpirerActor = "system"
+	// ExpiredReason reason of unbans made by expiration of timed bans
+	ExpiredReason = "expired"
+)
+
+// Ban parameters of login ban or unban, ban without Until is permanent,
+// not zero Version is compared with version of login before the change
+type Ban struct {
+	Actor   string
+	Until   *time.Time
+	Reason  string
+	Version int64
+}
+
+// applied returns true if login is already banned until the same time by the same reason,
+// times are compared to seconds, the precision of timestamps of mysql, so repeated ban changes nothing
+func (ban *Ban) applied(login *Login) bool {
+	if !login.Banned || login.BanReason != ban.Reason {
+		return false
+	}
+
+	if ban.Until == nil || login.BannedUntil == nil {
+		return ban.Until == nil && login.BannedUntil == nil
+	}
+
+	return ban.Until.Truncate(time.Second).Equal(login.BannedUntil.Truncate(time.Second))
+}
+
+// BanRecord history record of ban or unban of login
+type BanRecord struct {
 	Id        int64      `db:"-"`
-	Uuid      uuid.UUID  `db:"uuid"`
-	Login     string     `db:"login"`
+	LoginUuid uuid.UUID  `db:"login_uuid"`
 	Banned    bool       `db:"banned"`
+	Actor     string     `db:"actor"`
+	Reason    string     `db:"reason"`
+	Until     *time.Time `db:"banned_until"`
 	CreatedAt *time.Time `db:"created_at"`
-	UpdateAt  *time.Time `db:"update_at"`
+}
+
+// Alias former login of renamed login
+type Alias struct {
+	Id             int64      `db:"-"`
+	LoginUuid      uuid.UUID  `db:"login_uuid"`
+	Tenant         string     `db:"tenant"`
+	Login          string     `db:"login"`
+	LoginCanonical string     `db:"login_canonical"`
+	CreatedAt      *time.Time `db:"created_at"`
+}
+
+// Reserved login or glob pattern of logins which can't be registered, stored in canonical form
+type Reserved struct {
+	Id        int64      `db:"-"`
+	Pattern   string     `db:"pattern"`
+	Glob      bool       `db:"glob"`
+	CreatedAt *time.Time `db:"created_at"`
+}
+
+// NewReserved returns reserved entry of exact login or glob pattern with syntax of path.Match
+func NewReserved(pattern string) (*Reserved, error) {
+	canonical := Canonical(pattern)
+	if canonical == "" {
+		return nil, fmt.Errorf("%w: empty pattern", InvalidPatternError)
+	}
+
+	reserved := &Reserved{Pattern: canonical, Glob: strings.ContainsAny(canonical, "*?[\\")}
+
+	if reserved.Glob {
+		if _, err := path.Match(canonical, ""); err != nil {
+			return nil, fmt.Errorf("%w: %s", InvalidPatternError, pattern)
+		}
+	}
+
+	return reserved, nil
+}
+
+// Match reports whether canonical form of login is reserved by the entry
+func (reserved *Reserved) Match(canonical string) bool {
+	if !reserved.Glob {
+		return reserved.Pattern == canonical
+	}
+
+	matched, _ := path.Match(reserved.Pattern, canonical)
+
+	return matched
+}
+
+func newBanRecord(loginUuid uuid.UUID, banned bool, ban *Ban, now time.Time) *BanRecord {
+	record := &BanRecord{
+		LoginUuid: loginUuid,
+		Banned:    banned,
+		Actor:     ban.Actor,
+		Reason:    ban.Reason,
+		CreatedAt: &now,
+	}
+
+	if banned && ban.Until != nil {
+		until := ban.Until.In(time.UTC)
+		record.Until = &until
+	}
+
+	return record
+}
+
+// clone returns deep copy of login, used by memory repository to not share stored records
+func (login *Login) clone() *Login {
+	clone := *login
+
+	if login.BannedUntil != nil {
+		bannedUntil := *login.BannedUntil
+		clone.BannedUntil = &bannedUntil
+	}
+
+	if login.CreatedAt != nil {
+		createdAt := *login.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	if login.UpdateAt != nil {
+		updateAt := *login.UpdateAt
+		clone.UpdateAt = &updateAt
+	}
+
+	return &clone
+}
+
+// unban resetting ban state of login in memory repository
+func (login *Login) unban(now time.Time) {
+	login.Banned = false
+	login.BannedUntil = nil
+	login.BanReason = ""
+	login.Version++
+	login.UpdateAt = &now
+}
+
+// clone returns deep copy of ban record, used by memory repository to not share stored records
+func (record *BanRecord) clone() *BanRecord {
+	clone := *record
+
+	if record.Until != nil {
+		until := *record.Until
+		clone.Until = &until
+	}
+
+	if record.CreatedAt != nil {
+		createdAt := *record.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	return &clone
+}
+
+// clone returns deep copy of reserved entry, used by memory repository to not share stored records
+func (reserved *Reserved) clone() *Reserved {
+	clone := *reserved
+
+	if reserved.CreatedAt != nil {
+		createdAt := *reserved.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	return &clone
+}
+
+// clone returns deep copy of alias, used by memory repository to not share stored records
+func (alias *Alias) clone() *Alias {
+	clone := *alias
+
+	if alias.CreatedAt != nil {
+		createdAt := *alias.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	return &clone
 }
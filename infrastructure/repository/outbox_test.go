@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/diez37/go-packages/clients/db"
+	"testing"
+)
+
+func TestRepository_EventsAfter(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		for _, login := range []string{"alice", "bob"} {
+			if _, err := repository.Insert(ctx, &Login{Login: login}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// rolled back write leaves no gap in sequences
+		rollback := errors.New("rollback")
+
+		err := repository.Transaction(ctx, func(tx Repository) error {
+			if _, err := tx.Insert(ctx, &Login{Login: "carol"}); err != nil {
+				return err
+			}
+
+			return rollback
+		})
+		if err != rollback {
+			t.Fatalf("transaction: %v, expected error of fn", err)
+		}
+
+		if _, err := repository.Insert(ctx, &Login{Login: "dave"}); err != nil {
+			t.Fatal(err)
+		}
+
+		events, err := repository.EventsAfter(ctx, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(events) != 3 {
+			t.Fatalf("events %v, expected 3", eventTypes(events))
+		}
+
+		for index, event := range events {
+			if event.Sequence != events[0].Sequence+int64(index) {
+				t.Fatalf("sequence of event %d is %d, expected %d", index, event.Sequence, events[0].Sequence+int64(index))
+			}
+		}
+
+		last, err := repository.LastSequence(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if last != events[2].Sequence {
+			t.Fatalf("last sequence %d, expected %d", last, events[2].Sequence)
+		}
+
+		after, err := repository.EventsAfter(ctx, events[0].Sequence, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(after) != 1 || after[0].Sequence != events[1].Sequence {
+			t.Fatalf("events after %d limited by 1: %d, expected the one of sequence %d", events[0].Sequence, len(after), events[1].Sequence)
+		}
+	})
+}
+
+func TestRepository_EventsAfterTenant(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+		acme := WithTenant(ctx, "acme")
+
+		for _, write := range []struct {
+			ctx   context.Context
+			login string
+		}{{ctx, "alice"}, {acme, "bob"}, {ctx, "carol"}, {acme, "dave"}} {
+			if _, err := repository.Insert(write.ctx, &Login{Login: write.login}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, expected := range []struct {
+			ctx    context.Context
+			tenant string
+		}{{ctx, DefaultTenant}, {acme, "acme"}} {
+			events, err := repository.EventsAfter(expected.ctx, 0, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(events) != 2 {
+				t.Fatalf("events of tenant '%s': %d, expected 2", expected.tenant, len(events))
+			}
+
+			for _, event := range events {
+				if event.Tenant != expected.tenant {
+					t.Fatalf("event of tenant '%s' in events of tenant '%s'", event.Tenant, expected.tenant)
+				}
+			}
+		}
+
+		events, err := repository.AllEventsAfter(ctx, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(events) != 4 {
+			t.Fatalf("events of every tenant: %d, expected 4", len(events))
+		}
+	})
+}
+
+func TestRepository_EventsTransaction(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rollback := errors.New("rollback")
+
+		for _, write := range []struct {
+			event string
+			fn    func(tx Repository) error
+		}{
+			{LoginCreatedEvent, func(tx Repository) error {
+				_, err := tx.Insert(ctx, &Login{Login: "bob"})
+				return err
+			}},
+			{LoginUpdatedEvent, func(tx Repository) error {
+				login, err := tx.FindByUuid(ctx, inserted.Uuid)
+				if err != nil {
+					return err
+				}
+
+				login.Login = "carol"
+
+				_, err = tx.Update(ctx, login)
+				return err
+			}},
+			{LoginBannedEvent, func(tx Repository) error {
+				_, err := tx.BanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin", Reason: "spam"})
+				return err
+			}},
+			{LoginUnbannedEvent, func(tx Repository) error {
+				_, err := tx.UnbanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin"})
+				return err
+			}},
+		} {
+			before, err := repository.LastSequence(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// rolled back change leaves no event
+			err = repository.Transaction(ctx, func(tx Repository) error {
+				if err := write.fn(tx); err != nil {
+					return err
+				}
+
+				return rollback
+			})
+			if err != rollback {
+				t.Fatalf("transaction of %s: %v, expected error of fn", write.event, err)
+			}
+
+			if events, err := repository.EventsAfter(ctx, before, 100); err != nil || len(events) != 0 {
+				t.Fatalf("events of rolled back %s: %v, %v, expected none", write.event, eventTypes(events), err)
+			}
+
+			// committed change leaves exactly its event
+			if err := repository.Transaction(ctx, write.fn); err != nil {
+				t.Fatal(err)
+			}
+
+			events, err := repository.EventsAfter(ctx, before, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(eventTypes(events)) != fmt.Sprint([]string{write.event}) {
+				t.Fatalf("events of committed %s: %v", write.event, eventTypes(events))
+			}
+		}
+	})
+}
+
+func TestSql_EventsFailure(t *testing.T) {
+	database := newTestSqlite(t, "db")
+	repository := NewSql(database, nil, 0, testTracer)
+	ctx := context.Background()
+
+	inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every write of outbox fails from now on
+	_, err = database.Exec("CREATE TRIGGER outbox_failure BEFORE INSERT ON outbox BEGIN SELECT RAISE(ABORT, 'outbox'); END")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repository.Insert(ctx, &Login{Login: "bob"}); err == nil {
+		t.Fatal("insert is written without its event")
+	}
+
+	if _, err := repository.FindByLogin(ctx, "bob"); err != db.RecordNotFoundError {
+		t.Fatalf("find of login inserted without event: %v, expected db.RecordNotFoundError", err)
+	}
+
+	renamed := *inserted
+	renamed.Login = "carol"
+
+	if _, err := repository.Update(ctx, &renamed); err == nil {
+		t.Fatal("update is written without its event")
+	}
+
+	if _, err := repository.BanByUuid(ctx, inserted.Uuid, &Ban{Actor: "admin", Reason: "spam"}); err == nil {
+		t.Fatal("ban is written without its event")
+	}
+
+	found, err := repository.FindByUuid(ctx, inserted.Uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found.Login != "alice" || found.Banned || found.Version != inserted.Version {
+		t.Fatalf("login changed without event: '%s', banned %t, version %d", found.Login, found.Banned, found.Version)
+	}
+
+	bans, err := repository.CountBans(ctx, inserted.Uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bans != 0 {
+		t.Fatalf("bans written without event %d, expected 0", bans)
+	}
+}
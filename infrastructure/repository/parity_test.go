@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/google/uuid"
+	"strings"
+	"testing"
+	stdTime "time"
+)
+
+// describe returns fields of logins compared by parity of repositories, ids and times differ by engines
+func describe(logins ...*Login) string {
+	descriptions := make([]string, len(logins))
+	for index, login := range logins {
+		descriptions[index] = fmt.Sprintf(
+			"%s/%s v%d r%d banned=%t reason=%s until=%t",
+			login.Login, login.LoginCanonical, login.Version, login.Revision, login.Banned, login.BanReason, login.BannedUntil != nil,
+		)
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// parityScript running the same writes and reads by repository and returns trace of their results
+func parityScript(t *testing.T, repository Repository) []string {
+	ctx := context.Background()
+	var trace []string
+
+	record := func(operation string, result interface{}, err error) {
+		trace = append(trace, fmt.Sprintf("%s: %v, %v", operation, result, err))
+	}
+
+	until := time.NowUTC().Add(stdTime.Hour)
+	logins := map[string]*Login{}
+
+	for _, login := range []*Login{{Login: "alice"}, {Login: "Bob"}, {Login: "carol", Banned: true, BannedUntil: &until, BanReason: "spam"}} {
+		inserted, err := repository.Insert(ctx, login)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logins[inserted.LoginCanonical] = inserted
+		record("insert", describe(inserted), err)
+	}
+
+	_, err := repository.Insert(ctx, &Login{Login: "ALICE"})
+	record("insert duplicate", nil, err)
+
+	renamed := *logins["bob"]
+	renamed.Login = "bobby"
+	updated, err := repository.Update(ctx, &renamed)
+	record("rename", describe(updated), err)
+
+	_, err = repository.Update(ctx, logins["bob"])
+	record("update stale", nil, err)
+
+	for _, ban := range []struct {
+		name  string
+		login string
+		ban   func(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error)
+	}{
+		{"ban", "alice", repository.BanByUuid},
+		{"ban again", "alice", repository.BanByUuid},
+		{"unban", "carol", repository.UnbanByUuid},
+		{"unban again", "carol", repository.UnbanByUuid},
+	} {
+		banned, err := ban.ban(ctx, logins[ban.login].Uuid, &Ban{Actor: "admin", Reason: "abuse"})
+		record(ban.name, banned, err)
+	}
+
+	for _, login := range []string{"ALICE", "BOBBY", "bob"} {
+		found, err := repository.FindByLogin(ctx, login)
+		if err == nil {
+			record("find "+login, describe(found), err)
+			continue
+		}
+
+		record("find "+login, nil, err)
+	}
+
+	_, err = repository.FindByUuid(ctx, uuid.New())
+	record("find unknown uuid", nil, err)
+
+	banned := true
+	count, err := repository.Count(ctx, &Filter{Banned: &banned})
+	record("count banned", count, err)
+
+	page, err := repository.Page(ctx, &Filter{Sort: LoginSort, Desc: true}, 1, 2)
+	record("page", describe(page...), err)
+
+	filter := &Filter{Sort: LoginSort}
+	first, err := repository.Page(ctx, filter, 0, 2)
+	record("first page", describe(first...), err)
+
+	if len(first) > 0 {
+		next, err := repository.PageByCursor(ctx, filter, NewCursor(first[len(first)-1], filter), 2)
+		record("next page by cursor", describe(next...), err)
+	}
+
+	bans, err := repository.CountBans(ctx, logins["alice"].Uuid)
+	record("count bans", bans, err)
+
+	events, err := repository.EventsAfter(ctx, 0, 100)
+	record("events", eventTypes(events), err)
+
+	revision, err := repository.Revision(ctx)
+	record("revision", revision, err)
+
+	return trace
+}
+
+func TestRepository_Parity(t *testing.T) {
+	memory := parityScript(t, NewMemory(testTracer))
+	sql := parityScript(t, NewSql(newTestSqlite(t, "db"), nil, 0, testTracer))
+
+	if len(memory) != len(sql) {
+		t.Fatalf("memory made %d operations, sql %d", len(memory), len(sql))
+	}
+
+	for index := range memory {
+		if memory[index] != sql[index] {
+			t.Errorf("memory %s\n    sql %s", memory[index], sql[index])
+		}
+	}
+}
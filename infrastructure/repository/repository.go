@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 )
 
 type Finder interface {
@@ -15,18 +16,143 @@ type Saver interface {
 	Update(ctx context.Context, login *Login) (*Login, error)
 }
 
+// BatchSaver inserting logins by batches
+type BatchSaver interface {
+	// InsertBatch inserting logins in one transaction and returns error of every login in order of logins:
+	// nil for inserted login or ErrDuplicateLogin for already taken login, the returned error aborts the whole batch
+	InsertBatch(ctx context.Context, logins []*Login) ([]error, error)
+}
+
 type Blocker interface {
-	BanByUuid(ctx context.Context, uuid uuid.UUID) (bool, error)
+	BanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error)
+	UnbanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error)
+	// UnbanExpired lifting bans expired at the now and returns count of unbanned logins
+	UnbanExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// BanHistory history of bans and unbans of login
+type BanHistory interface {
+	CountBans(ctx context.Context, uuid uuid.UUID) (int64, error)
+	PageBans(ctx context.Context, uuid uuid.UUID, page uint, limit uint) ([]*BanRecord, error)
+	PageBansByCursor(ctx context.Context, uuid uuid.UUID, cursor *Cursor, limit uint) ([]*BanRecord, error)
 }
 
+// Paginator paging logins matched by Filter in its order
 type Paginator interface {
-	Count(ctx context.Context) (int64, error)
-	Page(ctx context.Context, page uint, limit uint) ([]*Login, error)
+	Count(ctx context.Context, filter *Filter) (int64, error)
+	Page(ctx context.Context, filter *Filter, page uint, limit uint) ([]*Login, error)
+	PageByCursor(ctx context.Context, filter *Filter, cursor *Cursor, limit uint) ([]*Login, error)
+}
+
+// Exporter streaming every login matched by Filter in its order without loading them all,
+// iteration stops at the first error of fn
+type Exporter interface {
+	Export(ctx context.Context, filter *Filter, fn func(login *Login) error) error
+}
+
+// Searcher searching logins by Search, results are ordered by match quality, length of login and id
+type Searcher interface {
+	CountSearch(ctx context.Context, search *Search) (int64, error)
+	Search(ctx context.Context, search *Search, page uint, limit uint) ([]*Login, error)
+}
+
+// Aliases former logins of renamed logins, recorded by Saver.Update
+type Aliases interface {
+	// FindAlias returns the latest alias with canonical form of login, db.RecordNotFoundError if there is no such alias
+	FindAlias(ctx context.Context, login string) (*Alias, error)
+}
+
+// Reservations reserved logins and glob patterns of logins which can't be registered
+type Reservations interface {
+	// MatchReserved returns entry reserving the login, db.RecordNotFoundError if login isn't reserved
+	MatchReserved(ctx context.Context, login string) (*Reserved, error)
+	ListReserved(ctx context.Context) ([]*Reserved, error)
+	AddReserved(ctx context.Context, reserved *Reserved) (*Reserved, error)
+	// RemoveReserved removing entry by pattern, db.RecordNotFoundError if pattern isn't reserved
+	RemoveReserved(ctx context.Context, pattern string) error
+}
+
+// Transactor running multi-step operations of Repository atomically,
+// calls of Transactor on Repository passed to fn join the current transaction
+type Transactor interface {
+	// Transaction running fn with Repository scoped by one transaction, committed if fn returns nil, rolled back otherwise
+	Transaction(ctx context.Context, fn func(repository Repository) error) error
+	// Snapshot running read-only fn with Repository scoped by one transaction, every read of which sees the same data
+	Snapshot(ctx context.Context, fn func(repository Repository) error) error
+}
+
+// Outbox events of changes of logins, written by Saver, BatchSaver and Blocker in the same transaction as the changes
+type Outbox interface {
+	// Unpublished returns up to limit unpublished events in order of their ids
+	Unpublished(ctx context.Context, limit uint) ([]*Event, error)
+	// MarkPublished marking events with ids as published
+	MarkPublished(ctx context.Context, ids ...int64) error
+	// EventsAfter returns up to limit events of logins of tenant of ctx with sequences greater than sequence
+	// in order of their sequences, sequences are taken in order of commits, so an event is never seen
+	// after events with greater sequences
+	EventsAfter(ctx context.Context, sequence int64, limit uint) ([]*Event, error)
+	// LastSequence returns sequence of the latest event, 0 if there are no events
+	LastSequence(ctx context.Context) (int64, error)
+	// AllEventsAfter the same as EventsAfter, but returns events of logins of every tenant
+	AllEventsAfter(ctx context.Context, sequence int64, limit uint) ([]*Event, error)
+	// Cursor returns sequence of the last event handled by consumer, 0 for a new consumer, must be called
+	// in transaction: cursor stays locked until its end, so concurrent instances of consumer handle every event once
+	Cursor(ctx context.Context, consumer string) (int64, error)
+	// MoveCursor setting sequence of the last event handled by consumer
+	MoveCursor(ctx context.Context, consumer string, sequence int64) error
+}
+
+// Webhooks subscriptions of webhooks of tenant of ctx to events of logins of the tenant and log of deliveries of the events
+type Webhooks interface {
+	ListWebhooks(ctx context.Context) ([]*Webhook, error)
+	// AllWebhooks the same as ListWebhooks, but returns webhooks of every tenant
+	AllWebhooks(ctx context.Context) ([]*Webhook, error)
+	// FindWebhook returns webhook by uuid, db.RecordNotFoundError if there is no such webhook
+	FindWebhook(ctx context.Context, uuid uuid.UUID) (*Webhook, error)
+	AddWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	// UpdateWebhook saving url, secret, events and activity of webhook, db.RecordNotFoundError if there is no such webhook
+	UpdateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	// RemoveWebhook removing webhook with its deliveries, db.RecordNotFoundError if there is no such webhook
+	RemoveWebhook(ctx context.Context, uuid uuid.UUID) error
+
+	AddDeliveries(ctx context.Context, deliveries ...*Delivery) error
+	// DueDeliveries returns up to limit pending deliveries with the next attempt not after now in order of their ids
+	DueDeliveries(ctx context.Context, now time.Time, limit uint) ([]*Delivery, error)
+	// ClaimDelivery moving the next attempt of due pending delivery to until, so other instances don't attempt it
+	// before the claim expires, returns false if delivery isn't due anymore: it's claimed by another instance
+	ClaimDelivery(ctx context.Context, delivery *Delivery, now time.Time, until time.Time) (bool, error)
+	// UpdateDelivery saving outcome of attempt of delivery
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+	CountDeliveries(ctx context.Context, webhookUuid uuid.UUID) (int64, error)
+	// PageDeliveries paging deliveries of webhook from the latest, io.EOF if page is empty
+	PageDeliveries(ctx context.Context, webhookUuid uuid.UUID, page uint, limit uint) ([]*Delivery, error)
+}
+
+// Revisions global revisions of writes of logins, every write of login by Saver, BatchSaver and Blocker
+// takes the next revision of the sequence
+type Revisions interface {
+	// Revision returns the latest committed revision, 0 if nothing was written
+	Revision(ctx context.Context) (int64, error)
+	// ChangedSince returns up to limit logins with revisions in (since, until] in order of their revisions,
+	// io.EOF if there are no such logins
+	ChangedSince(ctx context.Context, since int64, until int64, limit uint) ([]*Login, error)
 }
 
+// Repository reading and writing logins and webhooks of tenant of context set by WithTenant, UnbanExpired works
+// across tenants, reservations, outbox, deliveries and sequence of revisions are shared by tenants
 type Repository interface {
+	Transactor
 	Finder
 	Saver
+	BatchSaver
 	Blocker
+	BanHistory
 	Paginator
+	Exporter
+	Searcher
+	Reservations
+	Aliases
+	Outbox
+	Webhooks
+	Revisions
 }
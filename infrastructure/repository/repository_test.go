@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	stdSql "database/sql"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/Diez37/logins/infrastructure/database/postgres"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/diez37/go-packages/clients/db/mysql"
+	"github.com/diez37/go-packages/clients/db/sqlite"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/golang-migrate/migrate/v4"
+	migrateSqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.opentelemetry.io/otel/trace"
+	"path/filepath"
+	"testing"
+)
+
+// testMigrations source of migrations applied to sqlite databases of tests
+const testMigrations = "file://../../migrations/sqlite"
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testInformer writing info of database to log of test
+type testInformer struct {
+	t *testing.T
+}
+
+func (informer testInformer) Infof(format string, args ...interface{}) {
+	informer.t.Logf(format, args...)
+}
+
+func (informer testInformer) Info(args ...interface{}) {
+	informer.t.Log(args...)
+}
+
+// newTestSqlite returns database of sqlite file name in temporary directory of test with every migration applied
+func newTestSqlite(t *testing.T, name string) *goqu.Database {
+	t.Helper()
+
+	return openTestSqlite(t, "file:"+filepath.Join(t.TempDir(), name))
+}
+
+// openTestSqlite returns sqlite database of dsn with every migration applied
+func openTestSqlite(t *testing.T, dsn string) *goqu.Database {
+	t.Helper()
+
+	sqlDatabase, err := database.NewDatabase(
+		&db.Config{Driver: db.SQLiteDriver},
+		testInformer{t},
+		&mysql.Config{},
+		&sqlite.Config{Dsn: dsn},
+		&postgres.Config{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		sqlDatabase.Db.(*stdSql.DB).Close()
+	})
+
+	driver, err := migrateSqlite.WithInstance(sqlDatabase.Db.(*stdSql.DB), &migrateSqlite.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance, err := migrate.NewWithDatabaseInstance(testMigrations, db.SQLiteDriver, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := instance.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	return sqlDatabase
+}
+
+// testRepositories running test for memory repository and sql repository on sqlite
+func testRepositories(t *testing.T, test func(t *testing.T, repository Repository)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemory(testTracer))
+	})
+
+	t.Run("sql", func(t *testing.T) {
+		test(t, NewSql(newTestSqlite(t, "db"), nil, 0, testTracer))
+	})
+}
+
+func TestRepository_InsertFind(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		inserted, err := repository.Insert(ctx, &Login{Login: "Alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if inserted.Version != 1 || inserted.Tenant != DefaultTenant || inserted.LoginCanonical != "alice" {
+			t.Fatalf("inserted: version %d, tenant '%s', canonical '%s'", inserted.Version, inserted.Tenant, inserted.LoginCanonical)
+		}
+
+		found, err := repository.FindByUuid(ctx, inserted.Uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if found.Login != "Alice" {
+			t.Fatalf("found by uuid '%s', expected 'Alice'", found.Login)
+		}
+
+		found, err = repository.FindByLogin(ctx, "ALICE")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if found.Uuid != inserted.Uuid {
+			t.Fatalf("found by login %s, expected %s", found.Uuid, inserted.Uuid)
+		}
+
+		if _, err := repository.FindByLogin(ctx, "bob"); err != db.RecordNotFoundError {
+			t.Fatalf("find of unknown login: %v, expected db.RecordNotFoundError", err)
+		}
+
+		if _, err := repository.FindByLogin(WithTenant(ctx, "acme"), "alice"); err != db.RecordNotFoundError {
+			t.Fatalf("find of login of another tenant: %v, expected db.RecordNotFoundError", err)
+		}
+	})
+}
+
+func TestRepository_InsertDuplicate(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		if _, err := repository.Insert(ctx, &Login{Login: "alice"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repository.Insert(ctx, &Login{Login: "ALICE"}); !errors.Is(err, ErrDuplicateLogin) {
+			t.Fatalf("insert of the same canonical login: %v, expected ErrDuplicateLogin", err)
+		}
+
+		if _, err := repository.Insert(WithTenant(ctx, "acme"), &Login{Login: "alice"}); err != nil {
+			t.Fatalf("insert of the same login by another tenant: %v", err)
+		}
+	})
+}
+
+func TestRepository_UpdateVersion(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stale := *inserted
+
+		login := *inserted
+		login.Login = "carol"
+
+		updated, err := repository.Update(ctx, &login)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if updated.Version != inserted.Version+1 {
+			t.Fatalf("version after update %d, expected %d", updated.Version, inserted.Version+1)
+		}
+
+		stale.Login = "dave"
+
+		_, err = repository.Update(ctx, &stale)
+		if !errors.Is(err, VersionConflictError) {
+			t.Fatalf("update of stale version: %v, expected VersionConflictError", err)
+		}
+
+		found, err := repository.FindByLogin(ctx, "carol")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if found.Version != updated.Version {
+			t.Fatalf("found version %d, expected %d", found.Version, updated.Version)
+		}
+	})
+}
+
+func TestRepository_Transaction(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+		rollback := errors.New("rollback")
+
+		err := repository.Transaction(ctx, func(tx Repository) error {
+			if _, err := tx.Insert(ctx, &Login{Login: "alice"}); err != nil {
+				return err
+			}
+
+			if _, err := tx.FindByLogin(ctx, "alice"); err != nil {
+				return err
+			}
+
+			return rollback
+		})
+		if err != rollback {
+			t.Fatalf("transaction: %v, expected error of fn", err)
+		}
+
+		if _, err := repository.FindByLogin(ctx, "alice"); err != db.RecordNotFoundError {
+			t.Fatalf("find of login inserted by rolled back transaction: %v, expected db.RecordNotFoundError", err)
+		}
+	})
+}
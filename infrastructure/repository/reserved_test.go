@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/diez37/go-packages/clients/db"
+	"testing"
+)
+
+func TestNewReserved(t *testing.T) {
+	reserved, err := NewReserved("Admin*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reserved.Pattern != "admin*" || !reserved.Glob {
+		t.Fatalf("reserved '%s' glob %t, expected canonical glob 'admin*'", reserved.Pattern, reserved.Glob)
+	}
+
+	for _, pattern := range []string{"", "  ", "admin["} {
+		if _, err := NewReserved(pattern); !errors.Is(err, InvalidPatternError) {
+			t.Errorf("reserved '%s': %v, expected InvalidPatternError", pattern, err)
+		}
+	}
+}
+
+func TestRepository_Reserved(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		for _, pattern := range []string{"root", "admin*"} {
+			reserved, err := NewReserved(pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := repository.AddReserved(ctx, reserved); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		root, _ := NewReserved("ROOT")
+		if _, err := repository.AddReserved(ctx, root); !errors.Is(err, ErrDuplicateReserved) {
+			t.Fatalf("add of the same canonical pattern: %v, expected ErrDuplicateReserved", err)
+		}
+
+		for login, expected := range map[string]string{"Root": "root", "ADMIN": "admin*", "admin42": "admin*"} {
+			reserved, err := repository.MatchReserved(ctx, login)
+			if err != nil {
+				t.Fatalf("match of '%s': %v", login, err)
+			}
+
+			if reserved.Pattern != expected {
+				t.Errorf("'%s' is reserved by '%s', expected '%s'", login, reserved.Pattern, expected)
+			}
+		}
+
+		if _, err := repository.MatchReserved(ctx, "rooted"); err != db.RecordNotFoundError {
+			t.Fatalf("match of not reserved login: %v, expected db.RecordNotFoundError", err)
+		}
+
+		if err := repository.RemoveReserved(ctx, "root"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repository.RemoveReserved(ctx, "root"); err != db.RecordNotFoundError {
+			t.Fatalf("remove of removed pattern: %v, expected db.RecordNotFoundError", err)
+		}
+
+		if _, err := repository.MatchReserved(ctx, "root"); err != db.RecordNotFoundError {
+			t.Fatalf("match of removed pattern: %v, expected db.RecordNotFoundError", err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+// changed returns logins of ChangedSince by since and until
+func changed(t *testing.T, ctx context.Context, repository Repository, since int64, until int64) []string {
+	t.Helper()
+
+	logins, err := repository.ChangedSince(ctx, since, until, 10)
+	if err == io.EOF {
+		return nil
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(logins))
+	for index, login := range logins {
+		names[index] = login.Login
+	}
+
+	return names
+}
+
+// revision returns the latest revision of repository
+func revision(t *testing.T, repository Repository) int64 {
+	t.Helper()
+
+	revision, err := repository.Revision(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return revision
+}
+
+func TestRepository_Revision(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		if actual := revision(t, repository); actual != 0 {
+			t.Fatalf("revision of empty repository %d, expected 0", actual)
+		}
+
+		errs, err := repository.InsertBatch(ctx, []*Login{{Login: "alice"}, {Login: "bob"}})
+		if err != nil || errs[0] != nil || errs[1] != nil {
+			t.Fatalf("insert: %v %v", err, errs)
+		}
+
+		carol, err := repository.Insert(ctx, &Login{Login: "carol"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if carol.Revision != 3 || revision(t, repository) != 3 {
+			t.Fatalf("revision of the third write %d, expected 3", carol.Revision)
+		}
+
+		mark := revision(t, repository)
+
+		alice, err := repository.FindByLogin(ctx, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repository.BanByUuid(ctx, alice.Uuid, &Ban{Actor: "admin", Reason: "spam"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// repeated ban and unban of not banned login don't change logins
+		if _, err := repository.BanByUuid(ctx, alice.Uuid, &Ban{Actor: "admin", Reason: "spam"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repository.UnbanByUuid(ctx, carol.Uuid, &Ban{Actor: "admin"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := revision(t, repository); actual != mark+1 {
+			t.Fatalf("revision after one change %d, expected %d", actual, mark+1)
+		}
+
+		if actual := changed(t, ctx, repository, mark, revision(t, repository)); len(actual) != 1 || actual[0] != "alice" {
+			t.Fatalf("changed since high-water mark %v, expected [alice]", actual)
+		}
+
+		if _, err := repository.UnbanByUuid(ctx, alice.Uuid, &Ban{Actor: "admin"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// login changed twice since the mark is listed once by its latest revision
+		if actual := changed(t, ctx, repository, mark, revision(t, repository)); len(actual) != 1 || actual[0] != "alice" {
+			t.Fatalf("changed since high-water mark %v, expected [alice]", actual)
+		}
+
+		// revisions after until aren't listed, so page of changes is stable while writes go on
+		if actual := changed(t, ctx, repository, 0, mark); len(actual) != 2 || actual[0] != "bob" || actual[1] != "carol" {
+			t.Fatalf("changed until high-water mark %v, expected [bob carol]", actual)
+		}
+
+		if actual := changed(t, ctx, repository, revision(t, repository), revision(t, repository)); actual != nil {
+			t.Fatalf("changed since the latest revision %v, expected none", actual)
+		}
+
+		if actual := changed(t, WithTenant(ctx, "acme"), repository, 0, revision(t, repository)); actual != nil {
+			t.Fatalf("changed logins of another tenant %v, expected none", actual)
+		}
+	})
+}
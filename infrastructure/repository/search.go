@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// modes of Search
+const (
+	PrefixSearchMode   = "prefix"
+	ContainsSearchMode = "contains"
+	FuzzySearchMode    = "fuzzy"
+)
+
+// ranks of match quality of Search, lower is better
+const (
+	exactSearchRank = iota
+	prefixSearchRank
+	containsSearchRank
+	fuzzySearchRank
+	noSearchRank
+)
+
+// sqlLikeEscape escape character of LIKE patterns of Search
+const sqlLikeEscape = "!"
+
+// searchModes the worst rank matched by mode
+var searchModes = map[string]int{
+	PrefixSearchMode:   prefixSearchRank,
+	ContainsSearchMode: containsSearchRank,
+	FuzzySearchMode:    fuzzySearchRank,
+}
+
+// Search query of Searcher compared with canonical form of logins:
+// prefix matches logins starting with query, contains matches logins containing query
+// and fuzzy matches logins containing every character of query in the same order
+type Search struct {
+	Query string
+	Mode  string
+}
+
+func NewSearch(query string, mode string) (*Search, error) {
+	canonical := Canonical(query)
+	if canonical == "" {
+		return nil, fmt.Errorf("%w: empty query", InvalidSearchError)
+	}
+
+	if _, ok := searchModes[mode]; !ok {
+		return nil, fmt.Errorf("%w: unknown mode '%s'", InvalidSearchError, mode)
+	}
+
+	return &Search{Query: canonical, Mode: mode}, nil
+}
+
+// rank returns match quality of canonical form of login, noSearchRank if login isn't matched by the mode
+func (search *Search) rank(canonical string) int {
+	rank := noSearchRank
+
+	switch {
+	case canonical == search.Query:
+		rank = exactSearchRank
+	case strings.HasPrefix(canonical, search.Query):
+		rank = prefixSearchRank
+	case strings.Contains(canonical, search.Query):
+		rank = containsSearchRank
+	case subsequence(canonical, search.Query):
+		rank = fuzzySearchRank
+	}
+
+	if rank > searchModes[search.Mode] {
+		return noSearchRank
+	}
+
+	return rank
+}
+
+// likePatterns returns LIKE patterns of ranks matched by the mode, pattern of rank is placed at rank-1 index
+func (search *Search) likePatterns() []string {
+	query := likeEscape(search.Query)
+
+	patterns := []string{query + "%", "%" + query + "%"}
+
+	characters := make([]string, 0, len(search.Query))
+	for _, character := range search.Query {
+		characters = append(characters, likeEscape(string(character)))
+	}
+
+	patterns = append(patterns, "%"+strings.Join(characters, "%")+"%")
+
+	return patterns[:searchModes[search.Mode]]
+}
+
+// likeEscape escaping wildcards of LIKE pattern by sqlLikeEscape
+func likeEscape(value string) string {
+	return strings.NewReplacer(
+		sqlLikeEscape, sqlLikeEscape+sqlLikeEscape,
+		"%", sqlLikeEscape+"%",
+		"_", sqlLikeEscape+"_",
+	).Replace(value)
+}
+
+// subsequence reports whether every character of query is contained by value in the same order
+func subsequence(value string, query string) bool {
+	characters := []rune(query)
+
+	for _, character := range value {
+		if len(characters) == 0 {
+			break
+		}
+
+		if character == characters[0] {
+			characters = characters[1:]
+		}
+	}
+
+	return len(characters) == 0
+}
@@ -2,27 +2,215 @@ package repository
 
 import (
 	"context"
+	stdSql "database/sql"
+	"database/sql/driver"
+	"errors"
 	"github.com/Diez37/logins/infrastructure/time"
 	"github.com/diez37/go-packages/clients/db"
 	"github.com/doug-martin/goqu/v9"
-	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"io"
+	"math/rand"
+	stdTime "time"
 )
 
 const (
 	sqlTableName = "logins"
 )
 
+// sqlNextVersion incrementing version of updated login
+var sqlNextVersion = goqu.L("? + 1", goqu.I("version"))
+
+// errUnchanged rolling back transaction of write which found nothing to change
+var errUnchanged = errors.New("unchanged")
+
+// sqlTenant returns condition of logins of tenant of ctx
+func sqlTenant(ctx context.Context) goqu.Ex {
+	return goqu.Ex{"tenant": TenantFrom(ctx)}
+}
+
+// sqlColumns selected columns of logins table in order of scanning by scanLogin
+var sqlColumns = []interface{}{"id", "uuid", "tenant", "login", "login_canonical", "banned", "banned_until", "ban_reason", "version", "revision", "metadata", "created_at", "update_at"}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLogin(rows scanner) (*Login, error) {
+	login := &Login{}
+
+	err := rows.Scan(
+		&login.Id,
+		&login.Uuid,
+		&login.Tenant,
+		&login.Login,
+		&login.LoginCanonical,
+		&login.Banned,
+		&login.BannedUntil,
+		&login.BanReason,
+		&login.Version,
+		&login.Revision,
+		&login.Metadata,
+		&login.CreatedAt,
+		&login.UpdateAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+// executor common part of goqu.Database and goqu.TxDatabase
+type executor interface {
+	From(from ...interface{}) *goqu.SelectDataset
+	Update(table interface{}) *goqu.UpdateDataset
+	Insert(table interface{}) *goqu.InsertDataset
+	Delete(table interface{}) *goqu.DeleteDataset
+	ExecContext(ctx context.Context, query string, args ...interface{}) (stdSql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*stdSql.Rows, error)
+}
+
+// sqlBeginner beginning db transaction, pool of connections or dedicated connection
+type sqlBeginner interface {
+	BeginTx(ctx context.Context, options *stdSql.TxOptions) (*stdSql.Tx, error)
+}
+
+// sqlConnector pool of connections giving dedicated connection
+type sqlConnector interface {
+	Conn(ctx context.Context) (*stdSql.Conn, error)
+}
+
 type sql struct {
-	db     goqu.SQLDatabase
+	db     *goqu.Database
+	tx     *goqu.TxDatabase
+	router *sqlRouter
 	tracer trace.Tracer
 }
 
-func NewSql(db goqu.SQLDatabase, tracer trace.Tracer) Repository {
-	return &sql{db: db, tracer: tracer}
+// NewSql returns Repository writing to primary db and reading from replicas,
+// reads of session are sent to primary during readYourWrites after its writes
+func NewSql(db *goqu.Database, replicas []goqu.SQLDatabase, readYourWrites stdTime.Duration, tracer trace.Tracer) Repository {
+	return &sql{db: db, router: newSqlRouter(db, replicas, readYourWrites), tracer: tracer}
+}
+
+// executor returns transaction when repository is scoped by it, otherwise database
+func (repository *sql) executor() executor {
+	if repository.tx != nil {
+		return repository.tx
+	}
+
+	return repository.db
+}
+
+// reader returns transaction when repository is scoped by it, otherwise replica or primary chosen by router
+func (repository *sql) reader(ctx context.Context) executor {
+	if repository.tx != nil {
+		return repository.tx
+	}
+
+	return repository.router.reader(ctx, repository.db)
+}
+
+// transaction running fn with repository scoped by db transaction, joins the current transaction if exists
+func (repository *sql) transaction(ctx context.Context, fn func(repository *sql) error) error {
+	return repository.transactionWith(ctx, nil, fn)
+}
+
+// transactionWith the same as transaction, but begins db transaction with options,
+// read-only transaction is begun on replica chosen by router
+func (repository *sql) transactionWith(ctx context.Context, options *stdSql.TxOptions, fn func(repository *sql) error) error {
+	if repository.tx != nil {
+		return fn(repository)
+	}
+
+	readOnly := options != nil && options.ReadOnly
+
+	database := repository.db
+	if readOnly {
+		database = repository.router.reader(ctx, repository.db)
+	}
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		err = repository.begin(ctx, database, options, fn)
+		if readOnly || !sqlBusy(err) || attempt == sqlBusyAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-stdTime.After(stdTime.Duration(rand.Int63n(int64(attempt) * int64(sqlBusyBackoff)))):
+		}
+	}
+
+	if err == nil && !readOnly {
+		repository.router.wrote(ctx)
+	}
+
+	return err
+}
+
+// begin running fn in db transaction begun on dedicated connection of database, connection left in transaction
+// by failed commit or rollback is discarded, in pool its next begin would fail
+func (repository *sql) begin(ctx context.Context, database *goqu.Database, options *stdSql.TxOptions, fn func(repository *sql) error) error {
+	var beginner sqlBeginner = database.Db
+	discard := func() {}
+
+	if connector, ok := database.Db.(sqlConnector); ok {
+		connection, err := connector.Conn(ctx)
+		if err != nil {
+			return err
+		}
+
+		defer connection.Close()
+
+		beginner = connection
+		discard = func() {
+			_ = connection.Raw(func(interface{}) error {
+				return driver.ErrBadConn
+			})
+		}
+	}
+
+	sqlTx, err := beginner.BeginTx(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	tx := goqu.NewTx(database.Dialect(), sqlTx)
+
+	// transaction rolled back by done ctx has released its connection already
+	rollback := func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, stdSql.ErrTxDone) {
+			discard()
+		}
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&sql{db: repository.db, tx: tx, router: repository.router, tracer: repository.tracer}); err != nil {
+		rollback()
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		discard()
+
+		return err
+	}
+
+	return nil
 }
 
 func (repository *sql) FindByUuid(ctx context.Context, uuid uuid.UUID) (*Login, error) {
@@ -34,7 +222,7 @@ func (repository *sql) FindByUuid(ctx context.Context, uuid uuid.UUID) (*Login,
 		attribute.String("repository", "sql"),
 	)
 
-	sql, args, err := goqu.From(sqlTableName).Where(goqu.Ex{"uuid": uuid}).ToSQL()
+	sql, args, err := repository.executor().From(sqlTableName).Select(sqlColumns...).Where(sqlTenant(ctx), goqu.Ex{"uuid": uuid}).ToSQL()
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +239,7 @@ func (repository *sql) FindByLogin(ctx context.Context, login string) (*Login, e
 		attribute.String("repository", "sql"),
 	)
 
-	sql, args, err := goqu.From(sqlTableName).Where(goqu.Ex{"login": login}).ToSQL()
+	sql, args, err := repository.executor().From(sqlTableName).Select(sqlColumns...).Where(sqlTenant(ctx), goqu.Ex{"login_canonical": Canonical(login)}).ToSQL()
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +255,7 @@ func (repository *sql) find(ctx context.Context, sql string, args ...interface{}
 		attribute.String("repository", "sql"),
 	)
 
-	rows, err := repository.db.QueryContext(ctx, sql, args...)
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -75,52 +263,250 @@ func (repository *sql) find(ctx context.Context, sql string, args ...interface{}
 	defer rows.Close()
 
 	for rows.Next() {
-		login := &Login{}
-
-		if err := rows.Scan(&login.Id, &login.Uuid, &login.Login, &login.Banned, &login.CreatedAt, &login.UpdateAt); err != nil {
-			return nil, err
-		}
-
-		return login, nil
+		return scanLogin(rows)
 	}
 
 	return nil, db.RecordNotFoundError
 }
 
-func (repository *sql) BanByUuid(ctx context.Context, uuid uuid.UUID) (bool, error) {
+func (repository *sql) BanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error) {
 	ctx, span := repository.tracer.Start(ctx, "BanByUuid")
 	defer span.End()
 
 	span.SetAttributes(
 		attribute.String("uuid", uuid.String()),
+		attribute.String("reason", ban.Reason),
 		attribute.String("repository", "sql"),
 	)
 
-	sql, args, err := goqu.Update(sqlTableName).
-		Set(goqu.Record{"banned": true, "update_at": time.NowUTC()}).
-		Where(goqu.Ex{"uuid": uuid}).ToSQL()
-	if err != nil {
-		return false, err
-	}
+	err := repository.transaction(ctx, func(repository *sql) error {
+		stored, err := repository.FindByUuid(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		if ban.Version > 0 && stored.Version != ban.Version {
+			return VersionConflictError
+		}
+
+		if ban.applied(stored) {
+			return nil
+		}
+
+		revision, err := repository.nextRevisions(ctx, 1)
+		if err != nil {
+			return err
+		}
+
+		sql, args, err := repository.executor().Update(sqlTableName).
+			Set(goqu.Record{
+				"banned":       true,
+				"banned_until": ban.Until,
+				"ban_reason":   ban.Reason,
+				"version":      sqlNextVersion,
+				"revision":     revision,
+				"update_at":    time.NowUTC(),
+			}).
+			Where(sqlTenant(ctx), versioned(goqu.Ex{"uuid": uuid}, ban.Version)).ToSQL()
+		if err != nil {
+			return err
+		}
+
+		result, err := repository.executor().ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		countUpdatedRows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if countUpdatedRows == 0 {
+			if err := repository.notUpdated(ctx, uuid, ban.Version); err != nil {
+				return err
+			}
+
+			return VersionConflictError
+		}
 
-	result, err := repository.db.ExecContext(ctx, sql, args...)
+		if err := repository.appendBans(ctx, true, ban, uuid); err != nil {
+			return err
+		}
+
+		return repository.appendEventsByUuid(ctx, LoginBannedEvent, uuid)
+	})
 	if err != nil {
 		return false, err
 	}
 
-	countUpdatedRows, err := result.RowsAffected()
-	if err != nil {
+	return true, nil
+}
+
+// UnbanByUuid lifting ban of login and returns the ban state, not banned login is left untouched
+func (repository *sql) UnbanByUuid(ctx context.Context, uuid uuid.UUID, ban *Ban) (bool, error) {
+	ctx, span := repository.tracer.Start(ctx, "UnbanByUuid")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("reason", ban.Reason),
+		attribute.String("repository", "sql"),
+	)
+
+	err := repository.transaction(ctx, func(repository *sql) error {
+		stored, err := repository.FindByUuid(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		if ban.Version > 0 && stored.Version != ban.Version {
+			return VersionConflictError
+		}
+
+		// revision is taken only by a change of the login
+		if !stored.Banned {
+			return nil
+		}
+
+		revision, err := repository.nextRevisions(ctx, 1)
+		if err != nil {
+			return err
+		}
+
+		sql, args, err := repository.executor().Update(sqlTableName).
+			Set(goqu.Record{
+				"banned":       false,
+				"banned_until": nil,
+				"ban_reason":   "",
+				"version":      sqlNextVersion,
+				"revision":     revision,
+				"update_at":    time.NowUTC(),
+			}).
+			Where(sqlTenant(ctx), versioned(goqu.Ex{"uuid": uuid, "banned": true}, ban.Version)).ToSQL()
+		if err != nil {
+			return err
+		}
+
+		result, err := repository.executor().ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		countUpdatedRows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if countUpdatedRows == 0 {
+			if err := repository.notUpdated(ctx, uuid, ban.Version); err != nil {
+				return err
+			}
+
+			// unbanned concurrently, the rollback returns the taken revision
+			return errUnchanged
+		}
+
+		if err := repository.appendBans(ctx, false, ban, uuid); err != nil {
+			return err
+		}
+
+		return repository.appendEventsByUuid(ctx, LoginUnbannedEvent, uuid)
+	})
+	if err != nil && !errors.Is(err, errUnchanged) {
 		return false, err
 	}
 
-	if countUpdatedRows == 0 {
-		return false, db.RecordNotFoundError
+	return false, nil
+}
+
+func (repository *sql) UnbanExpired(ctx context.Context, now stdTime.Time) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "UnbanExpired")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("now", now.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	var uuids []uuid.UUID
+
+	err := repository.transaction(ctx, func(repository *sql) error {
+		expired := goqu.And(
+			goqu.Ex{"banned": true},
+			goqu.I("banned_until").IsNotNull(),
+			goqu.I("banned_until").Lte(now),
+		)
+
+		sql, args, err := repository.executor().From(sqlTableName).Select("uuid").Where(expired).ToSQL()
+		if err != nil {
+			return err
+		}
+
+		rows, err := repository.executor().QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var loginUuid uuid.UUID
+
+			if err := rows.Scan(&loginUuid); err != nil {
+				rows.Close()
+				return err
+			}
+
+			uuids = append(uuids, loginUuid)
+		}
+
+		if err := rows.Close(); err != nil {
+			return err
+		}
+
+		if len(uuids) == 0 {
+			return nil
+		}
+
+		revision, err := repository.nextRevisions(ctx, len(uuids))
+		if err != nil {
+			return err
+		}
+
+		// every login takes its own revision, so changes since a revision can be paged by revisions
+		for index, loginUuid := range uuids {
+			sql, args, err = repository.executor().Update(sqlTableName).
+				Set(goqu.Record{
+					"banned":       false,
+					"banned_until": nil,
+					"ban_reason":   "",
+					"version":      sqlNextVersion,
+					"revision":     revision + int64(index),
+					"update_at":    now,
+				}).
+				Where(expired, goqu.Ex{"uuid": loginUuid}).ToSQL()
+			if err != nil {
+				return err
+			}
+
+			if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+				return err
+			}
+		}
+
+		if err := repository.appendBans(ctx, false, &Ban{Actor: ExpirerActor, Reason: ExpiredReason}, uuids...); err != nil {
+			return err
+		}
+
+		return repository.appendEventsByUuid(ctx, LoginUnbannedEvent, uuids...)
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return true, nil
+	return int64(len(uuids)), nil
 }
 
-func (repository *sql) Count(ctx context.Context) (int64, error) {
+func (repository *sql) Count(ctx context.Context, filter *Filter) (int64, error) {
 	ctx, span := repository.tracer.Start(ctx, "Count")
 	defer span.End()
 
@@ -128,12 +514,19 @@ func (repository *sql) Count(ctx context.Context) (int64, error) {
 		attribute.String("repository", "sql"),
 	)
 
-	sql, _, err := goqu.From(sqlTableName).Select(goqu.COUNT("uuid")).ToSQL()
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(goqu.COUNT("uuid")).
+		Where(sqlTenant(ctx), sqlFilterWhere(repository.db.Dialect(), filter)).
+		ToSQL()
 	if err != nil {
 		return 0, err
 	}
 
-	rows, err := repository.db.QueryContext(ctx, sql)
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -153,7 +546,7 @@ func (repository *sql) Count(ctx context.Context) (int64, error) {
 	return 0, nil
 }
 
-func (repository *sql) Page(ctx context.Context, page uint, limit uint) ([]*Login, error) {
+func (repository *sql) Page(ctx context.Context, filter *Filter, page uint, limit uint) ([]*Login, error) {
 	ctx, span := repository.tracer.Start(ctx, "Page")
 	defer span.End()
 
@@ -163,15 +556,64 @@ func (repository *sql) Page(ctx context.Context, page uint, limit uint) ([]*Logi
 		attribute.String("repository", "sql"),
 	)
 
-	sql, args, err := goqu.From(sqlTableName).Where(
-		goqu.Ex{"id": goqu.Op{exp.GtOp.String(): page * limit}},
-		goqu.Ex{"id": goqu.Op{exp.LtOp.String(): ((page + 1) * limit) + 1}},
-	).ToSQL()
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(sqlColumns...).
+		Where(sqlTenant(ctx), sqlFilterWhere(repository.db.Dialect(), filter)).
+		Order(sqlOrder(filter)...).
+		Limit(limit).
+		Offset(page * limit).
+		ToSQL()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := repository.db.QueryContext(ctx, sql, args...)
+	return repository.page(ctx, sql, args...)
+}
+
+func (repository *sql) PageByCursor(ctx context.Context, filter *Filter, cursor *Cursor, limit uint) ([]*Login, error) {
+	ctx, span := repository.tracer.Start(ctx, "PageByCursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("cursor.id", cursor.Id),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := filter.checkCursor(cursor); err != nil {
+		return nil, err
+	}
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(sqlColumns...).
+		Where(sqlTenant(ctx), sqlFilterWhere(repository.db.Dialect(), filter), sqlCursorWhere(filter, cursor)).
+		Order(sqlOrder(filter)...).
+		Limit(limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.page(ctx, sql, args...)
+}
+
+func (repository *sql) page(ctx context.Context, sql string, args ...interface{}) ([]*Login, error) {
+	ctx, span := repository.tracer.Start(ctx, "page")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -181,15 +623,18 @@ func (repository *sql) Page(ctx context.Context, page uint, limit uint) ([]*Logi
 	var logins []*Login
 
 	for rows.Next() {
-		login := &Login{}
-
-		if err := rows.Scan(&login.Id, &login.Uuid, &login.Login, &login.Banned, &login.CreatedAt, &login.UpdateAt); err != nil {
+		login, err := scanLogin(rows)
+		if err != nil {
 			return nil, err
 		}
 
 		logins = append(logins, login)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	if len(logins) == 0 {
 		return nil, io.EOF
 	}
@@ -208,22 +653,80 @@ func (repository *sql) Update(ctx context.Context, login *Login) (*Login, error)
 
 	now := time.NowUTC()
 	login.UpdateAt = &now
+	login.LoginCanonical = Canonical(login.Login)
+	defaultMetadata(login)
 
-	sql, args, err := goqu.Update(sqlTableName).Set(login).Where(goqu.Ex{"uuid": login.Uuid}).ToSQL()
+	version := login.Version
 
-	if err != nil {
-		return nil, err
-	}
+	err := repository.transaction(ctx, func(repository *sql) error {
+		stored, err := repository.FindByUuid(ctx, login.Uuid)
+		if err != nil {
+			return err
+		}
 
-	result, err := repository.db.ExecContext(ctx, sql, args...)
+		if stored.Version != version {
+			return VersionConflictError
+		}
 
-	if countUpdate, err := result.RowsAffected(); err != nil {
-		return nil, err
-	} else if countUpdate == 0 {
-		return nil, db.RecordNotFoundError
+		login.Tenant = stored.Tenant
+		login.Version = version + 1
+
+		login.Revision, err = repository.nextRevisions(ctx, 1)
+		if err != nil {
+			return err
+		}
+
+		sql, args, err := repository.executor().Update(sqlTableName).
+			Set(login).
+			Where(sqlTenant(ctx), goqu.Ex{"uuid": login.Uuid, "version": version}).
+			ToSQL()
+		if err != nil {
+			return err
+		}
+
+		result, err := repository.executor().ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		countUpdatedRows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if countUpdatedRows == 0 {
+			return VersionConflictError
+		}
+
+		if stored.LoginCanonical != login.LoginCanonical {
+			err := repository.appendAlias(ctx, &Alias{
+				LoginUuid:      stored.Uuid,
+				Tenant:         stored.Tenant,
+				Login:          stored.Login,
+				LoginCanonical: stored.LoginCanonical,
+				CreatedAt:      &now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if stored.Banned != login.Banned {
+			err := repository.appendBans(ctx, login.Banned, &Ban{Until: login.BannedUntil, Reason: login.BanReason}, login.Uuid)
+			if err != nil {
+				return err
+			}
+		}
+
+		return repository.appendEvents(ctx, updatedEvent(stored, login), login)
+	})
+	if err != nil {
+		login.Version = version
+
+		return nil, duplicate(err)
 	}
 
-	return login, err
+	return login, nil
 }
 
 func (repository *sql) Insert(ctx context.Context, login *Login) (*Login, error) {
@@ -236,17 +739,66 @@ func (repository *sql) Insert(ctx context.Context, login *Login) (*Login, error)
 	)
 
 	login.Uuid = uuid.New()
+	login.Tenant = TenantFrom(ctx)
+	login.LoginCanonical = Canonical(login.Login)
+	login.Version = 1
+	defaultMetadata(login)
 
 	now := time.NowUTC()
 	login.CreatedAt = &now
 
-	sql, args, err := goqu.Insert(sqlTableName).Rows(login).ToSQL()
+	err := repository.transaction(ctx, func(repository *sql) error {
+		revision, err := repository.nextRevisions(ctx, 1)
+		if err != nil {
+			return err
+		}
+
+		login.Revision = revision
 
+		sql, args, err := repository.executor().Insert(sqlTableName).Rows(login).ToSQL()
+		if err != nil {
+			return err
+		}
+
+		if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+			return err
+		}
+
+		if login.Banned {
+			err := repository.appendBans(ctx, true, &Ban{Until: login.BannedUntil, Reason: login.BanReason}, login.Uuid)
+			if err != nil {
+				return err
+			}
+		}
+
+		return repository.appendEvents(ctx, LoginCreatedEvent, login)
+	})
 	if err != nil {
-		return nil, err
+		return nil, duplicate(err)
+	}
+
+	return login, nil
+}
+
+// versioned adding to condition expected version of login, zero version is not checked
+func versioned(where goqu.Ex, version int64) goqu.Ex {
+	if version > 0 {
+		where["version"] = version
 	}
 
-	_, err = repository.db.ExecContext(ctx, sql, args...)
+	return where
+}
+
+// notUpdated returns reason of not updated login by condition of versioned
+func (repository *sql) notUpdated(ctx context.Context, uuid uuid.UUID, version int64) error {
+	login, err := repository.FindByUuid(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	if version > 0 && login.Version != version {
+		return VersionConflictError
+	}
 
-	return login, err
+	return nil
 }
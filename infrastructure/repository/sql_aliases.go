@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/doug-martin/goqu/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	sqlAliasesTableName = "login_aliases"
+)
+
+// sqlAliasesColumns selected columns of login_aliases table in order of scanning by scanAlias
+var sqlAliasesColumns = []interface{}{"id", "login_uuid", "tenant", "login", "login_canonical", "created_at"}
+
+func scanAlias(rows scanner) (*Alias, error) {
+	alias := &Alias{}
+
+	err := rows.Scan(
+		&alias.Id,
+		&alias.LoginUuid,
+		&alias.Tenant,
+		&alias.Login,
+		&alias.LoginCanonical,
+		&alias.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// appendAlias appending former login of renamed login
+func (repository *sql) appendAlias(ctx context.Context, alias *Alias) error {
+	ctx, span := repository.tracer.Start(ctx, "appendAlias")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", alias.LoginUuid.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().Insert(sqlAliasesTableName).Rows(alias).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (repository *sql) FindAlias(ctx context.Context, login string) (*Alias, error) {
+	ctx, span := repository.tracer.Start(ctx, "FindAlias")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("login", login),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlAliasesTableName).
+		Select(sqlAliasesColumns...).
+		Where(sqlTenant(ctx), goqu.Ex{"login_canonical": Canonical(login)}).
+		Order(goqu.I("id").Desc()).
+		Limit(1).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repository.executor().QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		return scanAlias(rows)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, db.RecordNotFoundError
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+)
+
+const (
+	sqlBansTableName = "login_bans"
+)
+
+// sqlBansColumns selected columns of login_bans table in order of scanning by scanBanRecord
+var sqlBansColumns = []interface{}{"id", "login_uuid", "banned", "actor", "reason", "banned_until", "created_at"}
+
+func scanBanRecord(rows scanner) (*BanRecord, error) {
+	record := &BanRecord{}
+
+	err := rows.Scan(
+		&record.Id,
+		&record.LoginUuid,
+		&record.Banned,
+		&record.Actor,
+		&record.Reason,
+		&record.Until,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// appendBans appending history records of ban or unban of logins
+func (repository *sql) appendBans(ctx context.Context, banned bool, ban *Ban, uuids ...uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx, "appendBans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Bool("banned", banned),
+		attribute.Int("count", len(uuids)),
+		attribute.String("repository", "sql"),
+	)
+
+	now := time.NowUTC()
+
+	records := make([]interface{}, len(uuids))
+	for index, loginUuid := range uuids {
+		records[index] = newBanRecord(loginUuid, banned, ban, now)
+	}
+
+	sql, args, err := repository.executor().Insert(sqlBansTableName).Rows(records...).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (repository *sql) CountBans(ctx context.Context, uuid uuid.UUID) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "CountBans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlBansTableName).
+		Select(goqu.COUNT("id")).
+		Where(goqu.Ex{"login_uuid": uuid}).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		count := int64(0)
+
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+
+		return count, nil
+	}
+
+	return 0, nil
+}
+
+func (repository *sql) PageBans(ctx context.Context, uuid uuid.UUID, page uint, limit uint) ([]*BanRecord, error) {
+	ctx, span := repository.tracer.Start(ctx, "PageBans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlBansTableName).
+		Select(sqlBansColumns...).
+		Where(goqu.Ex{"login_uuid": uuid}).
+		Order(goqu.I("id").Asc()).
+		Limit(limit).
+		Offset(page * limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.pageBans(ctx, sql, args...)
+}
+
+func (repository *sql) PageBansByCursor(ctx context.Context, uuid uuid.UUID, cursor *Cursor, limit uint) ([]*BanRecord, error) {
+	ctx, span := repository.tracer.Start(ctx, "PageBansByCursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.Int64("cursor.id", cursor.Id),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlBansTableName).
+		Select(sqlBansColumns...).
+		Where(goqu.Ex{"login_uuid": uuid}, goqu.I("id").Gt(cursor.Id)).
+		Order(goqu.I("id").Asc()).
+		Limit(limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.pageBans(ctx, sql, args...)
+}
+
+func (repository *sql) pageBans(ctx context.Context, sql string, args ...interface{}) ([]*BanRecord, error) {
+	ctx, span := repository.tracer.Start(ctx, "pageBans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var records []*BanRecord
+
+	for rows.Next() {
+		record, err := scanBanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, io.EOF
+	}
+
+	return records, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (repository *sql) InsertBatch(ctx context.Context, logins []*Login) ([]error, error) {
+	ctx, span := repository.tracer.Start(ctx, "InsertBatch")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("count", len(logins)),
+		attribute.String("repository", "sql"),
+	)
+
+	errs, canonicals := newBatch(logins)
+	if len(canonicals) == 0 {
+		return errs, nil
+	}
+
+	err := repository.transaction(ctx, func(repository *sql) error {
+		sql, args, err := repository.executor().From(sqlTableName).
+			Select("login_canonical").
+			Where(sqlTenant(ctx), goqu.C("login_canonical").In(canonicals)).
+			ToSQL()
+		if err != nil {
+			return err
+		}
+
+		rows, err := repository.executor().QueryContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		taken := map[string]bool{}
+
+		for rows.Next() {
+			canonical := ""
+
+			if err := rows.Scan(&canonical); err != nil {
+				rows.Close()
+				return err
+			}
+
+			taken[canonical] = true
+		}
+
+		if err := rows.Close(); err != nil {
+			return err
+		}
+
+		now := time.NowUTC()
+
+		var inserted []interface{}
+		var created []*Login
+		var banned []*Login
+
+		for index, login := range logins {
+			if errs[index] != nil {
+				continue
+			}
+
+			if taken[login.LoginCanonical] {
+				errs[index] = ErrDuplicateLogin
+				continue
+			}
+
+			login.Uuid = uuid.New()
+			login.Tenant = TenantFrom(ctx)
+			defaultMetadata(login)
+			login.Version = 1
+			login.CreatedAt = &now
+
+			inserted = append(inserted, login)
+			created = append(created, login)
+
+			if login.Banned {
+				banned = append(banned, login)
+			}
+		}
+
+		if len(inserted) == 0 {
+			return nil
+		}
+
+		revision, err := repository.nextRevisions(ctx, len(created))
+		if err != nil {
+			return err
+		}
+
+		for index, login := range created {
+			login.Revision = revision + int64(index)
+		}
+
+		sql, args, err = repository.executor().Insert(sqlTableName).Rows(inserted...).ToSQL()
+		if err != nil {
+			return err
+		}
+
+		if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+			return err
+		}
+
+		for _, login := range banned {
+			if err := repository.appendBans(ctx, true, &Ban{Until: login.BannedUntil, Reason: login.BanReason}, login.Uuid); err != nil {
+				return err
+			}
+		}
+
+		return repository.appendEvents(ctx, LoginCreatedEvent, created...)
+	})
+	if err != nil {
+		return nil, duplicate(err)
+	}
+
+	return errs, nil
+}
+
+// newBatch setting canonical form of logins and returns errors of logins duplicated inside of the batch
+// with canonical forms of the rest logins
+func newBatch(logins []*Login) ([]error, []string) {
+	errs := make([]error, len(logins))
+	seen := map[string]bool{}
+
+	var canonicals []string
+
+	for index, login := range logins {
+		login.LoginCanonical = Canonical(login.Login)
+
+		if seen[login.LoginCanonical] {
+			errs[index] = ErrDuplicateLogin
+			continue
+		}
+
+		seen[login.LoginCanonical] = true
+		canonicals = append(canonicals, login.LoginCanonical)
+	}
+
+	return errs, canonicals
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	stdSql "database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	stdTime "time"
+)
+
+// testDialectUuid uuid of the login returned by recordingConn
+var testDialectUuid = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// recordingTime matches time literals of statements
+var recordingTime = regexp.MustCompile(`'\d{4}-\d{2}-\d{2}[T ][0-9:.]+(Z|[+-]\d{2}:\d{2})?'`)
+
+// recordingConn connection recording executed statements, queries of logins return not banned login
+// testDialectUuid, queries of sequences return 1
+type recordingConn struct {
+	quote      string
+	statements []string
+}
+
+func (conn *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("recording: statements aren't prepared")
+}
+
+func (conn *recordingConn) Close() error              { return nil }
+func (conn *recordingConn) Begin() (driver.Tx, error) { return conn, nil }
+func (conn *recordingConn) Commit() error             { return nil }
+func (conn *recordingConn) Rollback() error           { return nil }
+
+func (conn *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	conn.statements = append(conn.statements, query)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (conn *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	conn.statements = append(conn.statements, query)
+
+	switch {
+	case strings.Contains(query, "sequences"):
+		return &recordingRows{columns: []string{"value"}, values: [][]driver.Value{{int64(1)}}}, nil
+	case strings.HasPrefix(query, "SELECT "+conn.quote+"uuid"+conn.quote+" FROM"):
+		return &recordingRows{columns: []string{"uuid"}, values: [][]driver.Value{{testDialectUuid.String()}}}, nil
+	}
+
+	columns := make([]string, len(sqlColumns))
+	for index, column := range sqlColumns {
+		columns[index] = column.(string)
+	}
+
+	return &recordingRows{columns: columns, values: [][]driver.Value{{
+		int64(1), testDialectUuid.String(), DefaultTenant, "alice", "alice", false, nil, "",
+		int64(1), int64(1), "{}", stdTime.Now().UTC(), nil,
+	}}}, nil
+}
+
+func (conn *recordingConn) Connect(context.Context) (driver.Conn, error) { return conn, nil }
+func (conn *recordingConn) Driver() driver.Driver                        { return conn }
+func (conn *recordingConn) Open(string) (driver.Conn, error)             { return conn, nil }
+
+// recordingRows rows of recordingConn
+type recordingRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (rows *recordingRows) Columns() []string { return rows.columns }
+func (rows *recordingRows) Close() error      { return nil }
+
+func (rows *recordingRows) Next(dest []driver.Value) error {
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, rows.values[0])
+	rows.values = rows.values[1:]
+
+	return nil
+}
+
+// newTestDialect returns sql repository of dialect on recordingConn
+func newTestDialect(t *testing.T, dialect string) (Repository, *recordingConn) {
+	t.Helper()
+
+	conn := &recordingConn{quote: `"`}
+	if dialect == database.MySQLDialect {
+		conn.quote = "`"
+	}
+
+	db := stdSql.OpenDB(conn)
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return NewSql(goqu.New(dialect, db), nil, 0, testTracer), conn
+}
+
+// statement returns the first recorded statement starting with prefix
+func statement(t *testing.T, conn *recordingConn, prefix string) string {
+	t.Helper()
+
+	for _, statement := range conn.statements {
+		if strings.HasPrefix(statement, prefix) {
+			return statement
+		}
+	}
+
+	t.Fatalf("statement '%s...' isn't executed by %v", prefix, conn.statements)
+
+	return ""
+}
+
+func TestSql_DialectBanByUuid(t *testing.T) {
+	for dialect, expected := range map[string]string{
+		database.PostgresDialect: `UPDATE "logins" SET "ban_reason"='spam',"banned"=TRUE,"banned_until"=NULL,"revision"=1,` +
+			`"update_at"='<now>',"version"="version" + 1 WHERE (("tenant" = 'default') AND ("uuid" = '` + testDialectUuid.String() + `'))`,
+		database.MySQLDialect: "UPDATE `logins` SET `ban_reason`='spam',`banned`=1,`banned_until`=NULL,`revision`=1," +
+			"`update_at`='<now>',`version`=`version` + 1 WHERE ((`tenant` = 'default') AND (`uuid` = '" + testDialectUuid.String() + "'))",
+	} {
+		t.Run(dialect, func(t *testing.T) {
+			repository, conn := newTestDialect(t, dialect)
+
+			if _, err := repository.BanByUuid(context.Background(), testDialectUuid, &Ban{Actor: "admin", Reason: "spam"}); err != nil {
+				t.Fatal(err)
+			}
+
+			// update_at is the now of the ban
+			actual := recordingTime.ReplaceAllString(statement(t, conn, "UPDATE "+conn.quote+"logins"+conn.quote), "'<now>'")
+			if actual != expected {
+				t.Fatalf("ban:\n%s\nexpected:\n%s", actual, expected)
+			}
+		})
+	}
+}
+
+func TestSql_DialectUnbanExpired(t *testing.T) {
+	const postgresExpired = `("banned" IS TRUE) AND ("banned_until" IS NOT NULL) AND ("banned_until" <= '2026-10-17T12:00:00Z')`
+	const mysqlExpired = "(`banned` IS TRUE) AND (`banned_until` IS NOT NULL) AND (`banned_until` <= '2026-10-17 12:00:00')"
+
+	for dialect, expected := range map[string][]string{
+		database.PostgresDialect: {
+			`SELECT "uuid" FROM "logins" WHERE (` + postgresExpired + `)`,
+			`UPDATE "logins" SET "ban_reason"='',"banned"=FALSE,"banned_until"=NULL,"revision"=1,` +
+				`"update_at"='2026-10-17T12:00:00Z',"version"="version" + 1 ` +
+				`WHERE ((` + postgresExpired + `) AND ("uuid" = '` + testDialectUuid.String() + `'))`,
+		},
+		database.MySQLDialect: {
+			"SELECT `uuid` FROM `logins` WHERE (" + mysqlExpired + ")",
+			"UPDATE `logins` SET `ban_reason`='',`banned`=0,`banned_until`=NULL,`revision`=1," +
+				"`update_at`='2026-10-17 12:00:00',`version`=`version` + 1 " +
+				"WHERE ((" + mysqlExpired + ") AND (`uuid` = '" + testDialectUuid.String() + "'))",
+		},
+	} {
+		t.Run(dialect, func(t *testing.T) {
+			repository, conn := newTestDialect(t, dialect)
+
+			count, err := repository.UnbanExpired(context.Background(), stdTime.Date(2026, 10, 17, 12, 0, 0, 0, stdTime.UTC))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if count != 1 {
+				t.Fatalf("unbanned %d, expected 1", count)
+			}
+
+			if actual := statement(t, conn, "SELECT "+conn.quote+"uuid"+conn.quote); actual != expected[0] {
+				t.Fatalf("select of expired:\n%s\nexpected:\n%s", actual, expected[0])
+			}
+
+			if actual := statement(t, conn, "UPDATE "+conn.quote+"logins"+conn.quote); actual != expected[1] {
+				t.Fatalf("unban:\n%s\nexpected:\n%s", actual, expected[1])
+			}
+		})
+	}
+}
+
+func TestSql_DialectMetadataEq(t *testing.T) {
+	for dialect, expected := range map[string]string{
+		database.PostgresDialect: `SELECT * FROM "logins" WHERE ((jsonb_typeof(CAST("metadata" AS JSONB) -> 'tier') = 'string') AND ` +
+			`(CAST("metadata" AS JSONB) ->> 'tier' = 'gold'))`,
+		database.MySQLDialect: "SELECT * FROM `logins` WHERE ((JSON_TYPE(JSON_EXTRACT(`metadata`, '$.\\\"tier\\\"')) = 'STRING') AND " +
+			"(JSON_UNQUOTE(JSON_EXTRACT(`metadata`, '$.\\\"tier\\\"')) = 'gold'))",
+	} {
+		actual, _, err := goqu.Dialect(dialect).From(sqlTableName).Where(sqlMetadataEq(dialect, "tier", "gold")).ToSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual != expected {
+			t.Errorf("%s metadata condition:\n%s\nexpected:\n%s", dialect, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"strings"
+)
+
+const (
+	// pqUniqueViolation postgres error code of unique index violation
+	pqUniqueViolation = "23505"
+	// mysqlDuplicateEntry mysql error number of unique index violation
+	mysqlDuplicateEntry = 1062
+	// sqliteUniqueFailed prefix of sqlite error message of unique index violation
+	sqliteUniqueFailed = "UNIQUE constraint failed: "
+	// sqliteBusy sqlite error message of SQLITE_BUSY and its extended codes
+	sqliteBusy = "database is locked"
+)
+
+// sqlDuplicates duplicate errors by suffix of index or column name reported by driver
+var sqlDuplicates = map[string]*DuplicateError{
+	"login":           ErrDuplicateLogin,
+	"login_canonical": ErrDuplicateLogin,
+	"uuid":            ErrDuplicateUuid,
+	"pattern":         ErrDuplicateReserved,
+}
+
+// duplicate mapping unique index violation of any driver to DuplicateError, other errors are returned as is
+func duplicate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var name string
+
+	var pqError *pq.Error
+	var mysqlError *mysql.MySQLError
+
+	switch {
+	case errors.As(err, &pqError) && pqError.Code == pqUniqueViolation:
+		name = pqError.Constraint
+	case errors.As(err, &mysqlError) && mysqlError.Number == mysqlDuplicateEntry:
+		// Duplicate entry '...' for key 'logins.logins_login'
+		name = mysqlError.Message[strings.LastIndex(mysqlError.Message, " ")+1:]
+	case strings.Contains(err.Error(), sqliteUniqueFailed):
+		// UNIQUE constraint failed: logins.tenant, logins.login_canonical (2067), the last column tells the field
+		columns := err.Error()[strings.Index(err.Error(), sqliteUniqueFailed)+len(sqliteUniqueFailed):]
+		columns = strings.SplitN(columns, " (", 2)[0]
+		name = columns[strings.LastIndex(columns, " ")+1:]
+	default:
+		return err
+	}
+
+	name = strings.Trim(name, "'`\"")
+
+	for suffix, duplicateError := range sqlDuplicates {
+		if strings.HasSuffix(name, "_"+suffix) || strings.HasSuffix(name, "."+suffix) {
+			return duplicateError
+		}
+	}
+
+	return err
+}
+
+// sqlBusy returns true if err is failure of sqlite to take lock of database held by another connection
+func sqlBusy(err error) bool {
+	return err != nil && strings.Contains(err.Error(), sqliteBusy)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"testing"
+)
+
+func TestDuplicate(t *testing.T) {
+	other := errors.New("other")
+
+	for name, test := range map[string]struct {
+		err      error
+		expected error
+	}{
+		"postgres login":    {&pq.Error{Code: pqUniqueViolation, Constraint: "logins_tenant_login_canonical"}, ErrDuplicateLogin},
+		"postgres uuid":     {&pq.Error{Code: pqUniqueViolation, Constraint: "logins_uuid"}, ErrDuplicateUuid},
+		"postgres pattern":  {&pq.Error{Code: pqUniqueViolation, Constraint: "reserved_logins_pattern"}, ErrDuplicateReserved},
+		"postgres not null": {&pq.Error{Code: "23502", Constraint: "logins_uuid"}, nil},
+		"mysql 8 login": {
+			&mysql.MySQLError{Number: mysqlDuplicateEntry, Message: "Duplicate entry 'default-alice' for key 'logins.logins_tenant_login_canonical'"},
+			ErrDuplicateLogin,
+		},
+		"mysql 5 uuid": {
+			&mysql.MySQLError{Number: mysqlDuplicateEntry, Message: "Duplicate entry '00000000-0000-0000-0000-000000000001' for key 'logins_uuid'"},
+			ErrDuplicateUuid,
+		},
+		"sqlite login":   {errors.New("UNIQUE constraint failed: logins.tenant, logins.login_canonical (2067)"), ErrDuplicateLogin},
+		"sqlite pattern": {errors.New("UNIQUE constraint failed: reserved_logins.pattern"), ErrDuplicateReserved},
+		"other":          {other, nil},
+	} {
+		actual := duplicate(test.err)
+
+		// errors which aren't violations of known unique indexes are returned as is
+		expected := test.expected
+		if expected == nil {
+			expected = test.err
+		}
+
+		if actual != expected {
+			t.Errorf("%s: %v, expected %v", name, actual, expected)
+		}
+	}
+
+	if duplicate(nil) != nil {
+		t.Fatal("nil error is mapped")
+	}
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (repository *sql) Export(ctx context.Context, filter *Filter, fn func(login *Login) error) error {
+	ctx, span := repository.tracer.Start(ctx, "Export")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(sqlColumns...).
+		Where(sqlTenant(ctx), sqlFilterWhere(repository.db.Dialect(), filter)).
+		Order(sqlOrder(filter)...).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		login, err := scanLogin(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(login); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
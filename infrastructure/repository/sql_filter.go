@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// sqlSortable expression of sorted field of Filter
+type sqlSortable interface {
+	exp.Comparable
+	exp.Orderable
+}
+
+// sqlUpdated time of the last change of login, creation time for never updated logins
+var sqlUpdated = goqu.COALESCE(goqu.C("update_at"), goqu.C("created_at"))
+
+// sqlFilterWhere returns conditions of the filter in sql of dialect
+func sqlFilterWhere(dialect string, filter *Filter) exp.ExpressionList {
+	where := goqu.And()
+
+	if filter.Banned != nil {
+		where = where.Append(goqu.C("banned").Eq(*filter.Banned))
+	}
+
+	if filter.CreatedFrom != nil {
+		where = where.Append(goqu.C("created_at").Gte(filter.CreatedFrom.UTC()))
+	}
+
+	if filter.CreatedTo != nil {
+		where = where.Append(goqu.C("created_at").Lt(filter.CreatedTo.UTC()))
+	}
+
+	if filter.UpdatedFrom != nil {
+		where = where.Append(goqu.C("update_at").Gte(filter.UpdatedFrom.UTC()))
+	}
+
+	if filter.UpdatedTo != nil {
+		where = where.Append(goqu.C("update_at").Lt(filter.UpdatedTo.UTC()))
+	}
+
+	if prefix := Canonical(filter.LoginPrefix); prefix != "" {
+		where = where.Append(sqlSearchLike(likeEscape(prefix) + "%"))
+	}
+
+	if filter.MetadataKey != "" {
+		where = where.Append(sqlMetadataEq(dialect, filter.MetadataKey, filter.MetadataValue))
+	}
+
+	return where
+}
+
+// sqlMetadataEq returns condition of string value of key of metadata equal to value in sql of dialect
+func sqlMetadataEq(dialect string, key string, value string) exp.Expression {
+	path := metadataPath(key)
+
+	switch dialect {
+	case database.MySQLDialect:
+		extracted := goqu.L("JSON_EXTRACT(?, ?)", goqu.C("metadata"), path)
+
+		return goqu.And(
+			goqu.L("JSON_TYPE(?)", extracted).Eq("STRING"),
+			goqu.L("JSON_UNQUOTE(?)", extracted).Eq(value),
+		)
+	case database.PostgresDialect:
+		metadata := goqu.L("CAST(? AS JSONB)", goqu.C("metadata"))
+
+		return goqu.And(
+			goqu.L("jsonb_typeof(? -> ?)", metadata, key).Eq("string"),
+			goqu.L("? ->> ?", metadata, key).Eq(value),
+		)
+	}
+
+	return goqu.And(
+		goqu.L("json_type(?, ?)", goqu.C("metadata"), path).Eq("text"),
+		goqu.L("json_extract(?, ?)", goqu.C("metadata"), path).Eq(value),
+	)
+}
+
+// sqlSortField returns expression of sorted field of the filter
+func sqlSortField(filter *Filter) sqlSortable {
+	switch filter.sort() {
+	case LoginSort:
+		return goqu.C("login_canonical")
+	case CreatedSort:
+		return goqu.C("created_at")
+	case UpdatedSort:
+		return sqlUpdated
+	}
+
+	return goqu.C("id")
+}
+
+// sqlOrder returns order of the filter, logins with equal sorted field are ordered by id
+func sqlOrder(filter *Filter) []exp.OrderedExpression {
+	fields := []sqlSortable{sqlSortField(filter)}
+	if filter.sort() != IdSort {
+		fields = append(fields, goqu.C("id"))
+	}
+
+	order := make([]exp.OrderedExpression, len(fields))
+	for index, field := range fields {
+		if filter.Desc {
+			order[index] = field.Desc()
+		} else {
+			order[index] = field.Asc()
+		}
+	}
+
+	return order
+}
+
+// sqlCursorWhere returns condition of logins placed after the cursor in order of the filter
+func sqlCursorWhere(filter *Filter, cursor *Cursor) exp.Expression {
+	after := func(field exp.Comparable, value interface{}) exp.Expression {
+		if filter.Desc {
+			return field.Lt(value)
+		}
+
+		return field.Gt(value)
+	}
+
+	var value interface{}
+
+	switch filter.sort() {
+	case LoginSort:
+		value = cursor.Login
+	case CreatedSort, UpdatedSort:
+		value = cursor.Time.UTC()
+	default:
+		return after(goqu.C("id"), cursor.Id)
+	}
+
+	field := sqlSortField(filter)
+
+	return goqu.Or(
+		after(field, value),
+		goqu.And(field.Eq(value), after(goqu.C("id"), cursor.Id)),
+	)
+}
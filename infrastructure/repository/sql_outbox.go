@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+)
+
+const (
+	sqlOutboxTableName  = "outbox"
+	sqlCursorsTableName = "outbox_cursors"
+)
+
+// sqlOutboxColumns selected columns of outbox table in order of scanning by scanEvent
+var sqlOutboxColumns = []interface{}{"id", "sequence", "tenant", "type", "login_uuid", "payload", "created_at", "published_at"}
+
+func scanEvent(rows scanner) (*Event, error) {
+	event := &Event{}
+
+	err := rows.Scan(
+		&event.Id,
+		&event.Sequence,
+		&event.Tenant,
+		&event.Type,
+		&event.LoginUuid,
+		&event.Payload,
+		&event.CreatedAt,
+		&event.PublishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// appendEvents appending events of logins to outbox
+func (repository *sql) appendEvents(ctx context.Context, eventType string, logins ...*Login) error {
+	ctx, span := repository.tracer.Start(ctx, "appendEvents")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("type", eventType),
+		attribute.Int("count", len(logins)),
+		attribute.String("repository", "sql"),
+	)
+
+	if len(logins) == 0 {
+		return nil
+	}
+
+	sequence, err := repository.next(ctx, sqlOutboxSequence, len(logins))
+	if err != nil {
+		return err
+	}
+
+	now := time.NowUTC()
+
+	events := make([]interface{}, len(logins))
+	for index, login := range logins {
+		event, err := newEvent(eventType, login, now)
+		if err != nil {
+			return err
+		}
+
+		event.Sequence = sequence + int64(index)
+		events[index] = event
+	}
+
+	sql, args, err := repository.executor().Insert(sqlOutboxTableName).Rows(events...).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+// appendEventsByUuid appending events of logins with uuids in their current state
+func (repository *sql) appendEventsByUuid(ctx context.Context, eventType string, uuids ...uuid.UUID) error {
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(sqlColumns...).
+		Where(goqu.Ex{"uuid": uuids}).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	logins, err := repository.page(ctx, sql, args...)
+	if err == io.EOF {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return repository.appendEvents(ctx, eventType, logins...)
+}
+
+func (repository *sql) Unpublished(ctx context.Context, limit uint) ([]*Event, error) {
+	ctx, span := repository.tracer.Start(ctx, "Unpublished")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlOutboxTableName).
+		Select(sqlOutboxColumns...).
+		Where(goqu.I("published_at").IsNull()).
+		Order(goqu.I("id").Asc()).
+		Limit(limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.events(ctx, sql, args...)
+}
+
+func (repository *sql) MarkPublished(ctx context.Context, ids ...int64) error {
+	ctx, span := repository.tracer.Start(ctx, "MarkPublished")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("count", len(ids)),
+		attribute.String("repository", "sql"),
+	)
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sql, args, err := repository.executor().Update(sqlOutboxTableName).
+		Set(goqu.Record{"published_at": time.NowUTC()}).
+		Where(goqu.Ex{"id": ids}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (repository *sql) EventsAfter(ctx context.Context, sequence int64, limit uint) ([]*Event, error) {
+	ctx, span := repository.tracer.Start(ctx, "EventsAfter")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("sequence", sequence),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.eventsAfter(ctx, sequence, limit, sqlTenant(ctx))
+}
+
+func (repository *sql) AllEventsAfter(ctx context.Context, sequence int64, limit uint) ([]*Event, error) {
+	ctx, span := repository.tracer.Start(ctx, "AllEventsAfter")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("sequence", sequence),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.eventsAfter(ctx, sequence, limit)
+}
+
+// eventsAfter returns up to limit events matching conditions with sequences greater than sequence
+func (repository *sql) eventsAfter(ctx context.Context, sequence int64, limit uint, conditions ...goqu.Expression) ([]*Event, error) {
+	sql, args, err := repository.executor().From(sqlOutboxTableName).
+		Select(sqlOutboxColumns...).
+		Where(append(conditions, goqu.I("sequence").Gt(sequence))...).
+		Order(goqu.I("sequence").Asc()).
+		Limit(limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.eventsFrom(ctx, repository.reader(ctx), sql, args...)
+}
+
+func (repository *sql) LastSequence(ctx context.Context) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "LastSequence")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.sequence(ctx, repository.reader(ctx), sqlOutboxSequence)
+}
+
+func (repository *sql) Cursor(ctx context.Context, consumer string) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "Cursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("consumer", consumer),
+		attribute.String("repository", "sql"),
+	)
+
+	// update locks row of cursor until the end of transaction
+	sql, args, err := repository.executor().Update(sqlCursorsTableName).
+		Set(goqu.Record{"sequence": goqu.I("sequence")}).
+		Where(goqu.Ex{"consumer": consumer}).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := repository.executor().ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	countUpdatedRows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if countUpdatedRows == 0 {
+		sql, args, err := repository.executor().Insert(sqlCursorsTableName).
+			Rows(goqu.Record{"consumer": consumer, "sequence": 0}).
+			ToSQL()
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+		return 0, err
+	}
+
+	sql, args, err = repository.executor().From(sqlCursorsTableName).
+		Select("sequence").
+		Where(goqu.Ex{"consumer": consumer}).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := repository.executor().QueryContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		sequence := int64(0)
+
+		if err := rows.Scan(&sequence); err != nil {
+			return 0, err
+		}
+
+		return sequence, nil
+	}
+
+	return 0, rows.Err()
+}
+
+func (repository *sql) MoveCursor(ctx context.Context, consumer string, sequence int64) error {
+	ctx, span := repository.tracer.Start(ctx, "MoveCursor")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("consumer", consumer),
+		attribute.Int64("sequence", sequence),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().Update(sqlCursorsTableName).
+		Set(goqu.Record{"sequence": sequence}).
+		Where(goqu.Ex{"consumer": consumer}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (repository *sql) events(ctx context.Context, sql string, args ...interface{}) ([]*Event, error) {
+	return repository.eventsFrom(ctx, repository.executor(), sql, args...)
+}
+
+// eventsFrom returns events selected by sql from executor
+func (repository *sql) eventsFrom(ctx context.Context, executor executor, sql string, args ...interface{}) ([]*Event, error) {
+	rows, err := executor.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []*Event
+
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
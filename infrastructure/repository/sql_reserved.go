@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/doug-martin/goqu/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	sqlReservedTableName = "reserved_logins"
+)
+
+// sqlReservedColumns selected columns of reserved_logins table in order of scanning by scanReserved
+var sqlReservedColumns = []interface{}{"id", "pattern", "glob", "created_at"}
+
+func scanReserved(rows scanner) (*Reserved, error) {
+	reserved := &Reserved{}
+
+	err := rows.Scan(
+		&reserved.Id,
+		&reserved.Pattern,
+		&reserved.Glob,
+		&reserved.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reserved, nil
+}
+
+func (repository *sql) MatchReserved(ctx context.Context, login string) (*Reserved, error) {
+	ctx, span := repository.tracer.Start(ctx, "MatchReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("login", login),
+		attribute.String("repository", "sql"),
+	)
+
+	canonical := Canonical(login)
+
+	sql, args, err := repository.executor().From(sqlReservedTableName).
+		Select(sqlReservedColumns...).
+		Where(goqu.Or(
+			goqu.Ex{"pattern": canonical, "glob": false},
+			goqu.Ex{"glob": true},
+		)).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	reservations, err := repository.listReserved(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reserved := range reservations {
+		if reserved.Match(canonical) {
+			return reserved, nil
+		}
+	}
+
+	return nil, db.RecordNotFoundError
+}
+
+func (repository *sql) ListReserved(ctx context.Context) ([]*Reserved, error) {
+	ctx, span := repository.tracer.Start(ctx, "ListReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlReservedTableName).
+		Select(sqlReservedColumns...).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.listReserved(ctx, sql, args...)
+}
+
+func (repository *sql) listReserved(ctx context.Context, sql string, args ...interface{}) ([]*Reserved, error) {
+	rows, err := repository.executor().QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var reservations []*Reserved
+
+	for rows.Next() {
+		reserved, err := scanReserved(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, reserved)
+	}
+
+	return reservations, rows.Err()
+}
+
+func (repository *sql) AddReserved(ctx context.Context, reserved *Reserved) (*Reserved, error) {
+	ctx, span := repository.tracer.Start(ctx, "AddReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("pattern", reserved.Pattern),
+		attribute.String("repository", "sql"),
+	)
+
+	now := time.NowUTC()
+	reserved.CreatedAt = &now
+
+	sql, args, err := repository.executor().Insert(sqlReservedTableName).Rows(reserved).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+		return nil, duplicate(err)
+	}
+
+	return reserved, nil
+}
+
+func (repository *sql) RemoveReserved(ctx context.Context, pattern string) error {
+	ctx, span := repository.tracer.Start(ctx, "RemoveReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("pattern", pattern),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().Delete(sqlReservedTableName).
+		Where(goqu.Ex{"pattern": Canonical(pattern)}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	result, err := repository.executor().ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	countDeletedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if countDeletedRows == 0 {
+		return db.RecordNotFoundError
+	}
+
+	return nil
+}
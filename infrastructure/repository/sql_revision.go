@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	sqlSequencesTableName = "sequences"
+	// sqlRevisionSequence name of sequence of revisions of logins in sequences table
+	sqlRevisionSequence = "logins"
+	// sqlOutboxSequence name of sequence of events of outbox in sequences table
+	sqlOutboxSequence = "outbox"
+)
+
+// nextRevisions reserving count revisions and returns the first of them, the rest follow it one by one,
+// must be called in transaction: lock of row of sequence orders revisions by commits of writes
+func (repository *sql) nextRevisions(ctx context.Context, count int) (int64, error) {
+	return repository.next(ctx, sqlRevisionSequence, count)
+}
+
+// next reserving count values of sequence with name and returns the first of them, the rest follow it one by one,
+// row of sequence stays locked until commit of transaction, so values become visible in their order
+func (repository *sql) next(ctx context.Context, name string, count int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "next")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("name", name),
+		attribute.Int("count", count),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().Update(sqlSequencesTableName).
+		Set(goqu.Record{"value": goqu.L("value + ?", count)}).
+		Where(goqu.Ex{"name": name}).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+		return 0, err
+	}
+
+	value, err := repository.sequence(ctx, repository.executor(), name)
+	if err != nil {
+		return 0, err
+	}
+
+	return value - int64(count) + 1, nil
+}
+
+func (repository *sql) Revision(ctx context.Context) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "Revision")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.sequence(ctx, repository.reader(ctx), sqlRevisionSequence)
+}
+
+// sequence returns value of sequence with name read by executor
+func (repository *sql) sequence(ctx context.Context, executor executor, name string) (int64, error) {
+	sql, args, err := repository.executor().From(sqlSequencesTableName).
+		Select("value").
+		Where(goqu.Ex{"name": name}).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := executor.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		value := int64(0)
+
+		if err := rows.Scan(&value); err != nil {
+			return 0, err
+		}
+
+		return value, nil
+	}
+
+	return 0, rows.Err()
+}
+
+func (repository *sql) ChangedSince(ctx context.Context, since int64, until int64, limit uint) ([]*Login, error) {
+	ctx, span := repository.tracer.Start(ctx, "ChangedSince")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("since", since),
+		attribute.Int64("until", until),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(sqlColumns...).
+		Where(
+			sqlTenant(ctx),
+			goqu.I("revision").Gt(since),
+			goqu.I("revision").Lte(until),
+		).
+		Order(goqu.I("revision").Asc()).
+		Limit(limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.page(ctx, sql, args...)
+}
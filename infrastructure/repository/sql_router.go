@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/doug-martin/goqu/v9"
+	"sync"
+	"sync/atomic"
+	stdTime "time"
+)
+
+// sqlRouterSweepSize count of remembered sessions after which expired ones are forgotten on every write
+const sqlRouterSweepSize = 1024
+
+type sqlContextKey int
+
+const (
+	sqlSessionKey sqlContextKey = iota
+	sqlPrimaryKey
+)
+
+// WithSession returns ctx of session, reads of the session are sent to primary during read-your-writes period after its writes
+func WithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sqlSessionKey, session)
+}
+
+// WithPrimary returns ctx whose reads are always sent to primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sqlPrimaryKey, true)
+}
+
+// sqlRouter routing reads of sql to replicas by round robin and pinning sessions to primary after their writes
+type sqlRouter struct {
+	replicas       []*goqu.Database
+	next           uint32
+	readYourWrites stdTime.Duration
+
+	mutex  sync.Mutex
+	writes map[string]stdTime.Time // time of the last write by session
+}
+
+func newSqlRouter(primary *goqu.Database, replicas []goqu.SQLDatabase, readYourWrites stdTime.Duration) *sqlRouter {
+	router := &sqlRouter{readYourWrites: readYourWrites, writes: map[string]stdTime.Time{}}
+
+	for _, replica := range replicas {
+		router.replicas = append(router.replicas, goqu.New(primary.Dialect(), replica))
+	}
+
+	return router
+}
+
+// reader returns replica for reads of ctx, primary if there are no replicas or ctx is pinned to primary
+func (router *sqlRouter) reader(ctx context.Context, primary *goqu.Database) *goqu.Database {
+	if len(router.replicas) == 0 || router.pinned(ctx) {
+		return primary
+	}
+
+	return router.replicas[atomic.AddUint32(&router.next, 1)%uint32(len(router.replicas))]
+}
+
+func (router *sqlRouter) pinned(ctx context.Context) bool {
+	if primary, _ := ctx.Value(sqlPrimaryKey).(bool); primary {
+		return true
+	}
+
+	session, _ := ctx.Value(sqlSessionKey).(string)
+	if session == "" || router.readYourWrites <= 0 {
+		return false
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	wroteAt, ok := router.writes[session]
+
+	return ok && time.NowUTC().Sub(wroteAt) < router.readYourWrites
+}
+
+// wrote remembering time of write by session of ctx
+func (router *sqlRouter) wrote(ctx context.Context) {
+	session, _ := ctx.Value(sqlSessionKey).(string)
+	if session == "" || router.readYourWrites <= 0 || len(router.replicas) == 0 {
+		return
+	}
+
+	now := time.NowUTC()
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if len(router.writes) >= sqlRouterSweepSize {
+		for key, wroteAt := range router.writes {
+			if now.Sub(wroteAt) >= router.readYourWrites {
+				delete(router.writes, key)
+			}
+		}
+	}
+
+	router.writes[session] = now
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/doug-martin/goqu/v9"
+	"testing"
+	stdTime "time"
+)
+
+// newTestReplicated returns sql repository on sqlite primary with sqlite replica never receiving its writes,
+// so reads sent to replica don't see them
+func newTestReplicated(t *testing.T, readYourWrites stdTime.Duration) Repository {
+	replica := newTestSqlite(t, "replica")
+
+	return NewSql(newTestSqlite(t, "primary"), []goqu.SQLDatabase{replica.Db}, readYourWrites, testTracer)
+}
+
+func TestSqlRouter_Replica(t *testing.T) {
+	repository := newTestReplicated(t, stdTime.Minute)
+	ctx := context.Background()
+
+	inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repository.FindByUuid(ctx, inserted.Uuid); err != db.RecordNotFoundError {
+		t.Fatalf("read without session: %v, expected read of replica", err)
+	}
+
+	if _, err := repository.FindByUuid(WithPrimary(ctx), inserted.Uuid); err != nil {
+		t.Fatalf("read pinned to primary: %v", err)
+	}
+
+	// transaction is begun on primary
+	err = repository.Transaction(ctx, func(tx Repository) error {
+		_, err := tx.FindByUuid(ctx, inserted.Uuid)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("read in transaction: %v", err)
+	}
+}
+
+func TestSqlRouter_ReadYourWrites(t *testing.T) {
+	repository := newTestReplicated(t, stdTime.Minute)
+	ctx := context.Background()
+
+	inserted, err := repository.Insert(WithSession(ctx, "writer"), &Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repository.FindByUuid(WithSession(ctx, "writer"), inserted.Uuid); err != nil {
+		t.Fatalf("read of session after its write: %v, expected read of primary", err)
+	}
+
+	if _, err := repository.FindByUuid(WithSession(ctx, "reader"), inserted.Uuid); err != db.RecordNotFoundError {
+		t.Fatalf("read of another session: %v, expected read of replica", err)
+	}
+
+	if _, err := repository.FindByUuid(ctx, inserted.Uuid); err != db.RecordNotFoundError {
+		t.Fatalf("read without session: %v, expected read of replica", err)
+	}
+}
+
+func TestSqlRouter_ReadYourWritesExpired(t *testing.T) {
+	repository := newTestReplicated(t, stdTime.Millisecond)
+	ctx := WithSession(context.Background(), "writer")
+
+	inserted, err := repository.Insert(ctx, &Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdTime.Sleep(10 * stdTime.Millisecond)
+
+	if _, err := repository.FindByUuid(ctx, inserted.Uuid); err != db.RecordNotFoundError {
+		t.Fatalf("read of session after read-your-writes period: %v, expected read of replica", err)
+	}
+}
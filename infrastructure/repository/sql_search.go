@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// sqlSearchLike returns LIKE condition of canonical form of login
+func sqlSearchLike(pattern string) exp.LiteralExpression {
+	return goqu.L("? LIKE ? ESCAPE ?", goqu.C("login_canonical"), pattern, sqlLikeEscape)
+}
+
+// sqlSearchWhere returns condition of logins matched by the search
+func sqlSearchWhere(search *Search) exp.Expression {
+	patterns := search.likePatterns()
+
+	return sqlSearchLike(patterns[len(patterns)-1])
+}
+
+// sqlSearchRank returns expression of match quality of login by the search
+func sqlSearchRank(search *Search) exp.CaseExpression {
+	rank := goqu.Case().When(goqu.C("login_canonical").Eq(search.Query), exactSearchRank)
+
+	for index, pattern := range search.likePatterns() {
+		rank = rank.When(sqlSearchLike(pattern), index+1)
+	}
+
+	return rank.Else(noSearchRank)
+}
+
+// sqlSearchLength returns length of canonical form of login in characters in sql of dialect,
+// LENGTH of mysql counts bytes
+func sqlSearchLength(dialect string) exp.LiteralExpression {
+	if dialect == database.MySQLDialect {
+		return goqu.L("CHAR_LENGTH(?)", goqu.C("login_canonical"))
+	}
+
+	return goqu.L("LENGTH(?)", goqu.C("login_canonical"))
+}
+
+func (repository *sql) CountSearch(ctx context.Context, search *Search) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "CountSearch")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("query", search.Query),
+		attribute.String("mode", search.Mode),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(goqu.COUNT("uuid")).
+		Where(sqlTenant(ctx), sqlSearchWhere(search)).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		count := int64(0)
+
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+
+		return count, nil
+	}
+
+	return 0, rows.Err()
+}
+
+func (repository *sql) Search(ctx context.Context, search *Search, page uint, limit uint) ([]*Login, error) {
+	ctx, span := repository.tracer.Start(ctx, "Search")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("query", search.Query),
+		attribute.String("mode", search.Mode),
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlTableName).
+		Select(sqlColumns...).
+		Where(sqlTenant(ctx), sqlSearchWhere(search)).
+		Order(
+			sqlSearchRank(search).Asc(),
+			sqlSearchLength(repository.db.Dialect()).Asc(),
+			goqu.I("id").Asc(),
+		).
+		Limit(limit).
+		Offset(page * limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.page(ctx, sql, args...)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/doug-martin/goqu/v9"
+	"strings"
+	"testing"
+)
+
+func TestSqlSearchLength(t *testing.T) {
+	for dialect, expected := range map[string]string{
+		database.MySQLDialect:    "CHAR_LENGTH(`login_canonical`)",
+		database.PostgresDialect: `LENGTH("login_canonical")`,
+		database.SQLiteDialect:   "LENGTH(`login_canonical`)",
+	} {
+		sql, _, err := goqu.Dialect(dialect).From(sqlTableName).Order(sqlSearchLength(dialect).Asc()).ToSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasSuffix(sql, fmt.Sprintf("ORDER BY %s ASC", expected)) {
+			t.Errorf("%s: %s, expected order by %s", dialect, sql, expected)
+		}
+	}
+}
+
+func TestRepository_SearchOrder(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		for _, login := range []string{"bbal", "éal", "xal"} {
+			if _, err := repository.Insert(ctx, &Login{Login: login}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		search, err := NewSearch("al", ContainsSearchMode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logins, err := repository.Search(ctx, search, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var found []string
+		for _, login := range logins {
+			found = append(found, login.Login)
+		}
+
+		// matches of the same rank are ordered by length in characters, so two bytes of 'é' don't count twice
+		if fmt.Sprint(found) != "[éal xal bbal]" {
+			t.Fatalf("found %v, expected [éal xal bbal]", found)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	stdSql "database/sql"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+const (
+	// sqlBusyAttempts count of attempts of write transaction failed by locked sqlite database
+	sqlBusyAttempts = 10
+	// sqlBusyBackoff max delay before the second attempt of write transaction, increased by every next attempt,
+	// the delay is random up to it so transactions failed together don't collide again
+	sqlBusyBackoff = 20 * time.Millisecond
+)
+
+// sqlSnapshotOptions options of db transaction of Snapshot, read committed isolation
+// of postgres lets every statement see its own snapshot
+var sqlSnapshotOptions = &stdSql.TxOptions{Isolation: stdSql.LevelRepeatableRead, ReadOnly: true}
+
+func (repository *sql) Transaction(ctx context.Context, fn func(repository Repository) error) error {
+	ctx, span := repository.tracer.Start(ctx, "Transaction")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.transaction(ctx, func(repository *sql) error {
+		return fn(repository)
+	})
+}
+
+func (repository *sql) Snapshot(ctx context.Context, fn func(repository Repository) error) error {
+	ctx, span := repository.tracer.Start(ctx, "Snapshot")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.transactionWith(ctx, sqlSnapshotOptions, func(repository *sql) error {
+		return fn(repository)
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	stdSql "database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSql_ConcurrentUpdates(t *testing.T) {
+	for name, query := range map[string]string{"default dsn": "", "busy timeout of dsn": "?_pragma=busy_timeout(10000)"} {
+		t.Run(name, func(t *testing.T) {
+			repository := NewSql(openTestSqlite(t, "file:"+filepath.Join(t.TempDir(), "db")+query), nil, 0, testTracer)
+			ctx := context.Background()
+
+			logins := make([]*Login, 20)
+			for index := range logins {
+				login, err := repository.Insert(ctx, &Login{Login: fmt.Sprintf("login%d", index)})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				logins[index] = login
+			}
+
+			var wait sync.WaitGroup
+			errs := make([]error, len(logins))
+
+			// every transaction reads before its write like update of login by api
+			for index := range logins {
+				wait.Add(1)
+
+				go func(index int) {
+					defer wait.Done()
+
+					errs[index] = repository.Transaction(ctx, func(tx Repository) error {
+						login, err := tx.FindByUuid(ctx, logins[index].Uuid)
+						if err != nil {
+							return err
+						}
+
+						login.Login = fmt.Sprintf("renamed%d", index)
+
+						_, err = tx.Update(ctx, login)
+
+						return err
+					})
+				}(index)
+			}
+
+			wait.Wait()
+
+			for index, err := range errs {
+				if err != nil {
+					t.Errorf("update %d: %v", index, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSql_FailedCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+
+	sqlDatabase := openTestSqlite(t, "file:"+path+"?_pragma=busy_timeout(0)")
+	sqlDatabase.Db.(*stdSql.DB).SetMaxOpenConns(1)
+
+	repository := NewSql(sqlDatabase, nil, 0, testTracer)
+	ctx := context.Background()
+
+	for index := 0; index < 2; index++ {
+		if _, err := repository.Insert(ctx, &Login{Login: fmt.Sprintf("login%d", index)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// open statement of another connection holds read lock, commit can't take exclusive lock for its write
+	reader, err := stdSql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer reader.Close()
+
+	rows, err := reader.QueryContext(ctx, "SELECT login FROM logins")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !rows.Next() {
+		t.Fatal("logins aren't read")
+	}
+
+	if _, err := repository.Insert(ctx, &Login{Login: "alice"}); !sqlBusy(err) {
+		t.Fatalf("insert while database is read: %v, expected busy database", err)
+	}
+
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// connection left in transaction by failed commit isn't reused
+	if _, err := repository.Insert(ctx, &Login{Login: "alice"}); err != nil {
+		t.Fatalf("insert after read: %v", err)
+	}
+}
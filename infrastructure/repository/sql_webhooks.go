@@ -0,0 +1,488 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	stdTime "time"
+)
+
+const (
+	sqlWebhooksTableName   = "webhooks"
+	sqlDeliveriesTableName = "webhook_deliveries"
+)
+
+// sqlWebhooksColumns selected columns of webhooks table in order of scanning by scanWebhook
+var sqlWebhooksColumns = []interface{}{"id", "uuid", "tenant", "url", "secret", "events", "active", "created_at", "update_at"}
+
+// sqlDeliveriesColumns selected columns of webhook_deliveries table in order of scanning by scanDelivery
+var sqlDeliveriesColumns = []interface{}{
+	"id", "webhook_uuid", "type", "payload", "status", "attempts", "response_status", "error", "next_attempt_at", "created_at", "update_at",
+}
+
+func scanWebhook(rows scanner) (*Webhook, error) {
+	webhook := &Webhook{}
+
+	err := rows.Scan(
+		&webhook.Id,
+		&webhook.Uuid,
+		&webhook.Tenant,
+		&webhook.Url,
+		&webhook.Secret,
+		&webhook.Events,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdateAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func scanDelivery(rows scanner) (*Delivery, error) {
+	delivery := &Delivery{}
+
+	err := rows.Scan(
+		&delivery.Id,
+		&delivery.WebhookUuid,
+		&delivery.Type,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.Error,
+		&delivery.NextAttemptAt,
+		&delivery.CreatedAt,
+		&delivery.UpdateAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (repository *sql) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	ctx, span := repository.tracer.Start(ctx, "ListWebhooks")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.webhooks(ctx, sqlTenant(ctx))
+}
+
+func (repository *sql) AllWebhooks(ctx context.Context) ([]*Webhook, error) {
+	ctx, span := repository.tracer.Start(ctx, "AllWebhooks")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.webhooks(ctx, goqu.Ex{})
+}
+
+// webhooks returns webhooks matching condition in order of their ids
+func (repository *sql) webhooks(ctx context.Context, condition goqu.Ex) ([]*Webhook, error) {
+	sql, args, err := repository.executor().From(sqlWebhooksTableName).
+		Select(sqlWebhooksColumns...).
+		Where(condition).
+		Order(goqu.I("id").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repository.executor().QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var webhooks []*Webhook
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (repository *sql) FindWebhook(ctx context.Context, uuid uuid.UUID) (*Webhook, error) {
+	ctx, span := repository.tracer.Start(ctx, "FindWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlWebhooksTableName).
+		Select(sqlWebhooksColumns...).
+		Where(sqlTenant(ctx), goqu.Ex{"uuid": uuid}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repository.executor().QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		return scanWebhook(rows)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, db.RecordNotFoundError
+}
+
+func (repository *sql) AddWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	ctx, span := repository.tracer.Start(ctx, "AddWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("url", webhook.Url),
+		attribute.String("repository", "sql"),
+	)
+
+	webhook.Uuid = uuid.New()
+	webhook.Tenant = TenantFrom(ctx)
+
+	now := time.NowUTC()
+	webhook.CreatedAt = &now
+	webhook.UpdateAt = &now
+
+	sql, args, err := repository.executor().Insert(sqlWebhooksTableName).Rows(webhook).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+		return nil, duplicate(err)
+	}
+
+	return webhook, nil
+}
+
+func (repository *sql) UpdateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	ctx, span := repository.tracer.Start(ctx, "UpdateWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", webhook.Uuid.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	now := time.NowUTC()
+
+	sql, args, err := repository.executor().Update(sqlWebhooksTableName).
+		Set(goqu.Record{
+			"url":       webhook.Url,
+			"secret":    webhook.Secret,
+			"events":    webhook.Events,
+			"active":    webhook.Active,
+			"update_at": now,
+		}).
+		Where(sqlTenant(ctx), goqu.Ex{"uuid": webhook.Uuid}).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repository.executor().ExecContext(ctx, sql, args...); err != nil {
+		return nil, err
+	}
+
+	// affected rows can't tell missing webhook, mysql doesn't count rows matched without changes
+	return repository.FindWebhook(ctx, webhook.Uuid)
+}
+
+func (repository *sql) RemoveWebhook(ctx context.Context, uuid uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx, "RemoveWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", uuid.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	return repository.transaction(ctx, func(repository *sql) error {
+		sql, args, err := repository.executor().Delete(sqlWebhooksTableName).
+			Where(sqlTenant(ctx), goqu.Ex{"uuid": uuid}).
+			ToSQL()
+		if err != nil {
+			return err
+		}
+
+		result, err := repository.executor().ExecContext(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		countDeletedRows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if countDeletedRows == 0 {
+			return db.RecordNotFoundError
+		}
+
+		sql, args, err = repository.executor().Delete(sqlDeliveriesTableName).
+			Where(goqu.Ex{"webhook_uuid": uuid}).
+			ToSQL()
+		if err != nil {
+			return err
+		}
+
+		_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+		return err
+	})
+}
+
+func (repository *sql) AddDeliveries(ctx context.Context, deliveries ...*Delivery) error {
+	ctx, span := repository.tracer.Start(ctx, "AddDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("count", len(deliveries)),
+		attribute.String("repository", "sql"),
+	)
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	now := time.NowUTC()
+
+	rows := make([]interface{}, len(deliveries))
+	for index, delivery := range deliveries {
+		delivery.CreatedAt = &now
+		delivery.UpdateAt = &now
+
+		rows[index] = delivery
+	}
+
+	sql, args, err := repository.executor().Insert(sqlDeliveriesTableName).Rows(rows...).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (repository *sql) DueDeliveries(ctx context.Context, now stdTime.Time, limit uint) ([]*Delivery, error) {
+	ctx, span := repository.tracer.Start(ctx, "DueDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("now", now.String()),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlDeliveriesTableName).
+		Select(sqlDeliveriesColumns...).
+		Where(
+			goqu.Ex{"status": PendingDelivery},
+			goqu.I("next_attempt_at").Lte(now),
+		).
+		Order(goqu.I("id").Asc()).
+		Limit(limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := repository.deliveries(ctx, repository.executor(), sql, args...)
+	if err == io.EOF {
+		return nil, nil
+	}
+
+	return deliveries, err
+}
+
+func (repository *sql) ClaimDelivery(ctx context.Context, delivery *Delivery, now stdTime.Time, until stdTime.Time) (bool, error) {
+	ctx, span := repository.tracer.Start(ctx, "ClaimDelivery")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("id", delivery.Id),
+		attribute.String("until", until.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	// condition is checked again by concurrent update after the row is unlocked, so only one instance claims it
+	sql, args, err := repository.executor().Update(sqlDeliveriesTableName).
+		Set(goqu.Record{"next_attempt_at": until}).
+		Where(
+			goqu.Ex{"id": delivery.Id, "status": PendingDelivery},
+			goqu.I("next_attempt_at").Lte(now),
+		).
+		ToSQL()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := repository.executor().ExecContext(ctx, sql, args...)
+	if err != nil {
+		return false, err
+	}
+
+	countUpdatedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return countUpdatedRows > 0, nil
+}
+
+func (repository *sql) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	ctx, span := repository.tracer.Start(ctx, "UpdateDelivery")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("id", delivery.Id),
+		attribute.String("status", delivery.Status),
+		attribute.String("repository", "sql"),
+	)
+
+	now := time.NowUTC()
+	delivery.UpdateAt = &now
+
+	sql, args, err := repository.executor().Update(sqlDeliveriesTableName).
+		Set(goqu.Record{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_status": delivery.ResponseStatus,
+			"error":           delivery.Error,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"update_at":       now,
+		}).
+		Where(goqu.Ex{"id": delivery.Id}).
+		ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.executor().ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+func (repository *sql) CountDeliveries(ctx context.Context, webhookUuid uuid.UUID) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx, "CountDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", webhookUuid.String()),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlDeliveriesTableName).
+		Select(goqu.COUNT("id")).
+		Where(goqu.Ex{"webhook_uuid": webhookUuid}).
+		ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := repository.reader(ctx).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		count := int64(0)
+
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+
+		return count, nil
+	}
+
+	return 0, nil
+}
+
+func (repository *sql) PageDeliveries(ctx context.Context, webhookUuid uuid.UUID, page uint, limit uint) ([]*Delivery, error) {
+	ctx, span := repository.tracer.Start(ctx, "PageDeliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("uuid", webhookUuid.String()),
+		attribute.Int("page", int(page)),
+		attribute.Int("limit", int(limit)),
+		attribute.String("repository", "sql"),
+	)
+
+	sql, args, err := repository.executor().From(sqlDeliveriesTableName).
+		Select(sqlDeliveriesColumns...).
+		Where(goqu.Ex{"webhook_uuid": webhookUuid}).
+		Order(goqu.I("id").Desc()).
+		Limit(limit).
+		Offset(page * limit).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.deliveries(ctx, repository.reader(ctx), sql, args...)
+}
+
+// deliveries returns deliveries selected by sql, io.EOF if there are no deliveries
+func (repository *sql) deliveries(ctx context.Context, executor executor, sql string, args ...interface{}) ([]*Delivery, error) {
+	rows, err := executor.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []*Delivery
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, io.EOF
+	}
+
+	return deliveries, nil
+}
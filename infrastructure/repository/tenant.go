@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// DefaultTenant tenant of logins written and read without tenant in context
+const DefaultTenant = "default"
+
+// tenantPattern lowercase letters, digits, '-' and '_' up to 64 characters starting with letter or digit
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type tenantContextKey struct{}
+
+// WithTenant returns ctx of tenant, Repository reads and writes only logins of the tenant of ctx
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFrom returns tenant of ctx, DefaultTenant if ctx has no tenant
+func TenantFrom(ctx context.Context) string {
+	if tenant, _ := ctx.Value(tenantContextKey{}).(string); tenant != "" {
+		return tenant
+	}
+
+	return DefaultTenant
+}
+
+// CheckTenant returns InvalidTenantError if tenant isn't lowercase letters, digits, '-' and '_' up to 64 characters
+func CheckTenant(tenant string) error {
+	if !tenantPattern.MatchString(tenant) {
+		return fmt.Errorf("%w: '%s' must be up to 64 lowercase letters, digits, '-' and '_'", InvalidTenantError, tenant)
+	}
+
+	return nil
+}
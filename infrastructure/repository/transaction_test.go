@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRepository_TransactionJoined(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		alice, err := repository.Insert(ctx, &Login{Login: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rollback := errors.New("rollback")
+
+		err = repository.Transaction(ctx, func(tx Repository) error {
+			renamed := *alice
+			renamed.Login = "alicia"
+
+			if _, err := tx.Update(ctx, &renamed); err != nil {
+				return err
+			}
+
+			// nested transaction joins the current one, so its writes are rolled back with it
+			err := tx.Transaction(ctx, func(tx Repository) error {
+				_, err := tx.BanByUuid(ctx, alice.Uuid, &Ban{Actor: "admin", Reason: "spam"})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			return rollback
+		})
+		if err != rollback {
+			t.Fatalf("transaction: %v, expected error of fn", err)
+		}
+
+		found, err := repository.FindByUuid(ctx, alice.Uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if found.Login != "alice" || found.Banned || found.Version != alice.Version {
+			t.Fatalf("login after rollback '%s' banned %t version %d, expected untouched", found.Login, found.Banned, found.Version)
+		}
+
+		if count, err := repository.CountBans(ctx, alice.Uuid); err != nil || count != 0 {
+			t.Fatalf("bans after rollback %d: %v, expected 0", count, err)
+		}
+
+		if _, err := repository.FindAlias(ctx, "alice"); err == nil {
+			t.Fatal("alias of rolled back rename is found")
+		}
+	})
+}
+
+func TestRepository_TransactionPanic(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("panic of fn isn't propagated")
+				}
+			}()
+
+			_ = repository.Transaction(ctx, func(tx Repository) error {
+				if _, err := tx.Insert(ctx, &Login{Login: "alice"}); err != nil {
+					return err
+				}
+
+				panic("fn")
+			})
+		}()
+
+		if _, err := repository.FindByLogin(ctx, "alice"); err == nil {
+			t.Fatal("login inserted by panicked transaction is found")
+		}
+
+		// repository is usable after the panic
+		if _, err := repository.Insert(ctx, &Login{Login: "bob"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestRepository_Snapshot(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		if _, err := repository.Insert(ctx, &Login{Login: "alice"}); err != nil {
+			t.Fatal(err)
+		}
+
+		err := repository.Snapshot(ctx, func(tx Repository) error {
+			count, err := tx.Count(ctx, &Filter{})
+			if err != nil {
+				return err
+			}
+
+			if count != 1 {
+				t.Errorf("count in snapshot %d, expected 1", count)
+			}
+
+			_, err = tx.FindByLogin(ctx, "alice")
+
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
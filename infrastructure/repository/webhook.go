@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// statuses of Delivery
+const (
+	PendingDelivery   = "pending"
+	DeliveredDelivery = "delivered"
+	FailedDelivery    = "failed"
+)
+
+// Webhook subscription of url to events of logins of its tenant, requests are signed by Secret
+type Webhook struct {
+	Id        int64      `db:"-"`
+	Uuid      uuid.UUID  `db:"uuid"`
+	Tenant    string     `db:"tenant"`
+	Url       string     `db:"url"`
+	Secret    string     `db:"secret"`
+	Events    string     `db:"events"` // comma separated types of events, empty for every type
+	Active    bool       `db:"active"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdateAt  *time.Time `db:"update_at"`
+}
+
+// Delivery of event to webhook, keeps the outcome of the last attempt,
+// pending delivery is attempted again at NextAttemptAt
+type Delivery struct {
+	Id             int64      `db:"-"`
+	WebhookUuid    uuid.UUID  `db:"webhook_uuid"`
+	Type           string     `db:"type"`
+	Payload        string     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	ResponseStatus int        `db:"response_status"`
+	Error          string     `db:"error"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at"`
+	CreatedAt      *time.Time `db:"created_at"`
+	UpdateAt       *time.Time `db:"update_at"`
+}
+
+// JoinEvents returns types of events in form of Webhook.Events
+func JoinEvents(types []string) string {
+	return strings.Join(types, ",")
+}
+
+// EventTypes returns types of events the webhook is subscribed to, empty for every type
+func (webhook *Webhook) EventTypes() []string {
+	if webhook.Events == "" {
+		return []string{}
+	}
+
+	return strings.Split(webhook.Events, ",")
+}
+
+// Subscribed reports whether active webhook receives events of the type
+func (webhook *Webhook) Subscribed(eventType string) bool {
+	if !webhook.Active {
+		return false
+	}
+
+	if webhook.Events == "" {
+		return true
+	}
+
+	for _, subscribed := range webhook.EventTypes() {
+		if subscribed == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clone returns deep copy of webhook, used by memory repository to not share stored records
+func (webhook *Webhook) clone() *Webhook {
+	clone := *webhook
+
+	if webhook.CreatedAt != nil {
+		createdAt := *webhook.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	if webhook.UpdateAt != nil {
+		updateAt := *webhook.UpdateAt
+		clone.UpdateAt = &updateAt
+	}
+
+	return &clone
+}
+
+// clone returns deep copy of delivery, used by memory repository to not share stored records
+func (delivery *Delivery) clone() *Delivery {
+	clone := *delivery
+
+	if delivery.NextAttemptAt != nil {
+		nextAttemptAt := *delivery.NextAttemptAt
+		clone.NextAttemptAt = &nextAttemptAt
+	}
+
+	if delivery.CreatedAt != nil {
+		createdAt := *delivery.CreatedAt
+		clone.CreatedAt = &createdAt
+	}
+
+	if delivery.UpdateAt != nil {
+		updateAt := *delivery.UpdateAt
+		clone.UpdateAt = &updateAt
+	}
+
+	return &clone
+}
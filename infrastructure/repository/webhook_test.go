@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/clients/db"
+	"testing"
+	stdTime "time"
+)
+
+func TestRepository_Cursor(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		for _, expected := range []int64{0, 42} {
+			var sequence int64
+
+			err := repository.Transaction(ctx, func(tx Repository) error {
+				var err error
+
+				sequence, err = tx.Cursor(ctx, "test")
+				if err != nil {
+					return err
+				}
+
+				return tx.MoveCursor(ctx, "test", 42)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if sequence != expected {
+				t.Fatalf("cursor %d, expected %d", sequence, expected)
+			}
+		}
+	})
+}
+
+func TestRepository_ClaimDelivery(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+
+		webhook, err := repository.AddWebhook(ctx, &Webhook{Url: "http://localhost/", Secret: "secret", Active: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		now := time.NowUTC()
+
+		err = repository.AddDeliveries(ctx, &Delivery{
+			WebhookUuid:   webhook.Uuid,
+			Type:          LoginCreatedEvent,
+			Payload:       "{}",
+			Status:        PendingDelivery,
+			NextAttemptAt: &now,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		due, err := repository.DueDeliveries(ctx, now, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(due) != 1 {
+			t.Fatalf("due deliveries %d, expected 1", len(due))
+		}
+
+		until := now.Add(stdTime.Minute)
+
+		for attempt, expected := range []bool{true, false} {
+			claimed, err := repository.ClaimDelivery(ctx, due[0], now, until)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if claimed != expected {
+				t.Fatalf("claim %d: %t, expected %t", attempt+1, claimed, expected)
+			}
+		}
+
+		due, err = repository.DueDeliveries(ctx, now, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(due) != 0 {
+			t.Fatalf("due claimed deliveries %d, expected 0", len(due))
+		}
+
+		// expired claim is claimed again
+		due, err = repository.DueDeliveries(ctx, until, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(due) != 1 {
+			t.Fatalf("due deliveries after claim %d, expected 1", len(due))
+		}
+
+		claimed, err := repository.ClaimDelivery(ctx, due[0], until, until.Add(stdTime.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !claimed {
+			t.Fatal("expired claim isn't claimed again")
+		}
+	})
+}
+
+func TestRepository_WebhooksTenant(t *testing.T) {
+	testRepositories(t, func(t *testing.T, repository Repository) {
+		ctx := context.Background()
+		acme := WithTenant(ctx, "acme")
+
+		webhook, err := repository.AddWebhook(acme, &Webhook{Url: "http://localhost/", Secret: "secret", Active: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if webhook.Tenant != "acme" {
+			t.Fatalf("tenant of webhook '%s', expected 'acme'", webhook.Tenant)
+		}
+
+		if _, err := repository.AddWebhook(ctx, &Webhook{Url: "http://localhost/", Secret: "secret", Active: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		webhooks, err := repository.ListWebhooks(acme)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(webhooks) != 1 || webhooks[0].Uuid != webhook.Uuid {
+			t.Fatalf("webhooks of tenant %d, expected the one of tenant", len(webhooks))
+		}
+
+		if _, err := repository.FindWebhook(ctx, webhook.Uuid); err != db.RecordNotFoundError {
+			t.Fatalf("find of webhook of another tenant: %v, expected db.RecordNotFoundError", err)
+		}
+
+		if _, err := repository.UpdateWebhook(ctx, webhook); err != db.RecordNotFoundError {
+			t.Fatalf("update of webhook of another tenant: %v, expected db.RecordNotFoundError", err)
+		}
+
+		if err := repository.RemoveWebhook(ctx, webhook.Uuid); err != db.RecordNotFoundError {
+			t.Fatalf("remove of webhook of another tenant: %v, expected db.RecordNotFoundError", err)
+		}
+
+		all, err := repository.AllWebhooks(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(all) != 2 {
+			t.Fatalf("webhooks of every tenant %d, expected 2", len(all))
+		}
+
+		if err := repository.RemoveWebhook(acme, webhook.Uuid); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
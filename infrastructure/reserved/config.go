@@ -0,0 +1,16 @@
+package reserved
+
+const (
+	SeedFieldName = "reserved.seed"
+
+	SeedDefault = ""
+)
+
+type Config struct {
+	// Seed path to file of reserved logins and glob patterns, one per line, empty path is not seeded
+	Seed string
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
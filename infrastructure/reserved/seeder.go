@@ -0,0 +1,100 @@
+package reserved
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+	"strings"
+)
+
+// commentPrefix prefix of comment lines of seed file
+const commentPrefix = "#"
+
+// Seeder adding reserved logins from seed file, already reserved patterns are skipped
+type Seeder struct {
+	config       *Config
+	reservations repository.Reservations
+	tracer       trace.Tracer
+	logger       log.Logger
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Seeder {
+	configurator.SetDefault(SeedFieldName, SeedDefault)
+
+	if seed := configurator.GetString(SeedFieldName); seed != "" && config.Seed == SeedDefault {
+		config.Seed = seed
+	}
+
+	return NewSeeder(config, repository, tracer, logger)
+}
+
+func NewSeeder(config *Config, reservations repository.Reservations, tracer trace.Tracer, logger log.Logger) *Seeder {
+	return &Seeder{config: config, reservations: reservations, tracer: tracer, logger: logger}
+}
+
+// Seed adding every pattern of Config.Seed file
+func (seeder *Seeder) Seed(ctx context.Context) error {
+	if seeder.config.Seed == "" {
+		return nil
+	}
+
+	ctx, span := seeder.tracer.Start(ctx, "Seed")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("seed", seeder.config.Seed))
+
+	file, err := os.Open(seeder.config.Seed)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	count, added := 0, 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+
+		count++
+
+		reserved, err := repository.NewReserved(line)
+		if err != nil {
+			return err
+		}
+
+		_, err = seeder.reservations.AddReserved(ctx, reserved)
+		if errors.Is(err, repository.ErrDuplicateReserved) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		added++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	seeder.logger.Infof("reserved.seed: added %d of %d patterns from '%s'", added, count, seeder.config.Seed)
+
+	return nil
+}
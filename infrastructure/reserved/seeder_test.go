@@ -0,0 +1,63 @@
+package reserved
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"go.opentelemetry.io/otel/trace"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+func TestSeeder_Seed(t *testing.T) {
+	seed := filepath.Join(t.TempDir(), "reserved.txt")
+	if err := ioutil.WriteFile(seed, []byte("# system logins\nroot\n\n  Admin*  \nROOT\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	memory := repository.NewMemory(testTracer)
+	seeder := NewSeeder(&Config{Seed: seed}, memory, testTracer, testLogger{t})
+	ctx := context.Background()
+
+	// seeding again skips already reserved patterns
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := seeder.Seed(ctx); err != nil {
+			t.Fatalf("seed %d: %v", attempt+1, err)
+		}
+	}
+
+	reservations, err := memory.ListReserved(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reservations) != 2 || reservations[0].Pattern != "root" || reservations[1].Pattern != "admin*" {
+		t.Fatalf("reservations %d, expected root and admin*", len(reservations))
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.txt")
+	if err := ioutil.WriteFile(invalid, []byte("admin[\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewSeeder(&Config{Seed: invalid}, memory, testTracer, testLogger{t}).Seed(ctx); err == nil {
+		t.Fatal("invalid pattern is seeded")
+	}
+}
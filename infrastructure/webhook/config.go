@@ -0,0 +1,38 @@
+package webhook
+
+import "time"
+
+const (
+	TimeoutFieldName     = "webhook.timeout"
+	MaxAttemptsFieldName = "webhook.max_attempts"
+	BackoffFieldName     = "webhook.backoff"
+	MaxBackoffFieldName  = "webhook.max_backoff"
+	IntervalFieldName    = "webhook.interval"
+	BatchSizeFieldName   = "webhook.batch_size"
+
+	TimeoutDefault     = 5 * time.Second
+	MaxAttemptsDefault = 8
+	BackoffDefault     = time.Second
+	MaxBackoffDefault  = time.Hour
+	IntervalDefault    = time.Second
+	BatchSizeDefault   = uint(100)
+)
+
+type Config struct {
+	// Timeout timeout of request to webhook
+	Timeout time.Duration
+	// MaxAttempts count of attempts of delivery before it's failed
+	MaxAttempts int
+	// Backoff delay before the second attempt of delivery, doubled by every next attempt
+	Backoff time.Duration
+	// MaxBackoff max delay between attempts of delivery
+	MaxBackoff time.Duration
+	// Interval between checks of due deliveries
+	Interval time.Duration
+	// BatchSize max count of deliveries attempted by one check
+	BatchSize uint
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
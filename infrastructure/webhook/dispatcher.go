@@ -0,0 +1,399 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
+	"github.com/diez37/go-packages/configurator"
+	"github.com/diez37/go-packages/log"
+	"github.com/go-http-utils/headers"
+	"github.com/google/uuid"
+	"github.com/ldez/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strconv"
+	stdTime "time"
+)
+
+const (
+	// maxErrorLength max length of error of attempt kept by delivery log
+	maxErrorLength = 1024
+	// CursorConsumer name of cursor of events of outbox scheduled for delivery to webhooks
+	CursorConsumer = "webhooks"
+)
+
+// Dispatcher delivering events of logins from outbox to subscribed webhooks of tenants of the logins, failed deliveries
+// are attempted again with exponential backoff until Config.MaxAttempts, every delivery is made at least once
+type Dispatcher struct {
+	config     *Config
+	repository repository.Repository
+	client     *http.Client
+	tracer     trace.Tracer
+	logger     log.Logger
+}
+
+func WithConfigurator(
+	configurator configurator.Configurator,
+	config *Config,
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+) *Dispatcher {
+	configurator.SetDefault(TimeoutFieldName, TimeoutDefault)
+	configurator.SetDefault(MaxAttemptsFieldName, MaxAttemptsDefault)
+	configurator.SetDefault(BackoffFieldName, BackoffDefault)
+	configurator.SetDefault(MaxBackoffFieldName, MaxBackoffDefault)
+	configurator.SetDefault(IntervalFieldName, IntervalDefault)
+	configurator.SetDefault(BatchSizeFieldName, BatchSizeDefault)
+
+	if timeout := configurator.GetDuration(TimeoutFieldName); timeout > 0 && config.Timeout == TimeoutDefault {
+		config.Timeout = timeout
+	}
+
+	if maxAttempts := configurator.GetInt(MaxAttemptsFieldName); maxAttempts > 0 && config.MaxAttempts == MaxAttemptsDefault {
+		config.MaxAttempts = maxAttempts
+	}
+
+	if backoff := configurator.GetDuration(BackoffFieldName); backoff > 0 && config.Backoff == BackoffDefault {
+		config.Backoff = backoff
+	}
+
+	if maxBackoff := configurator.GetDuration(MaxBackoffFieldName); maxBackoff > 0 && config.MaxBackoff == MaxBackoffDefault {
+		config.MaxBackoff = maxBackoff
+	}
+
+	if interval := configurator.GetDuration(IntervalFieldName); interval > 0 && config.Interval == IntervalDefault {
+		config.Interval = interval
+	}
+
+	if batchSize := configurator.GetUint(BatchSizeFieldName); batchSize > 0 && config.BatchSize == BatchSizeDefault {
+		config.BatchSize = batchSize
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = TimeoutDefault
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = MaxAttemptsDefault
+	}
+
+	if config.Backoff <= 0 {
+		config.Backoff = BackoffDefault
+	}
+
+	if config.MaxBackoff < config.Backoff {
+		config.MaxBackoff = config.Backoff
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = IntervalDefault
+	}
+
+	if config.BatchSize == 0 {
+		config.BatchSize = BatchSizeDefault
+	}
+
+	return NewDispatcher(config, repository, &http.Client{Timeout: config.Timeout}, tracer, logger)
+}
+
+func NewDispatcher(config *Config, repository repository.Repository, client *http.Client, tracer trace.Tracer, logger log.Logger) *Dispatcher {
+	logger.Infof(
+		"webhook: timeout - %s, max attempts - %d, backoff - %s, max backoff - %s, interval - %s, batch size - %d",
+		config.Timeout,
+		config.MaxAttempts,
+		config.Backoff,
+		config.MaxBackoff,
+		config.Interval,
+		config.BatchSize,
+	)
+
+	return &Dispatcher{
+		config:     config,
+		repository: repository,
+		client:     client,
+		tracer:     tracer,
+		logger:     logger,
+	}
+}
+
+// Run scheduling deliveries of new events of outbox and attempting due deliveries every Config.Interval until ctx is done
+func (dispatcher *Dispatcher) Run(ctx context.Context) error {
+	ticker := stdTime.NewTicker(dispatcher.config.Interval)
+	defer ticker.Stop()
+
+	dispatcher.logger.Info("webhook.dispatcher: started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			dispatcher.logger.Info("webhook.dispatcher: shutdown")
+
+			return nil
+		case <-ticker.C:
+		}
+
+		for dispatcher.schedule(ctx) {
+		}
+
+		for dispatcher.dispatch(ctx) {
+		}
+	}
+}
+
+// schedule adding deliveries of one batch of events of outbox after cursor to subscribed webhooks and moving
+// the cursor in one transaction, returns true if the next batch may be ready
+func (dispatcher *Dispatcher) schedule(ctx context.Context) bool {
+	ctx, span := dispatcher.tracer.Start(ctx, "schedule")
+	defer span.End()
+
+	var events []*repository.Event
+
+	err := dispatcher.repository.Transaction(ctx, func(tx repository.Repository) error {
+		after, err := tx.Cursor(ctx, CursorConsumer)
+		if err != nil {
+			return err
+		}
+
+		events, err = tx.AllEventsAfter(ctx, after, dispatcher.config.BatchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		webhooks, err := tx.AllWebhooks(ctx)
+		if err != nil {
+			return err
+		}
+
+		deliveries, err := dispatcher.deliveries(webhooks, events)
+		if err != nil {
+			return err
+		}
+
+		if len(deliveries) > 0 {
+			if err := tx.AddDeliveries(ctx, deliveries...); err != nil {
+				return err
+			}
+		}
+
+		return tx.MoveCursor(ctx, CursorConsumer, events[len(events)-1].Sequence)
+	})
+	if err != nil {
+		dispatcher.logger.Error(err)
+		return false
+	}
+
+	span.SetAttributes(attribute.Int("count", len(events)))
+
+	return uint(len(events)) == dispatcher.config.BatchSize
+}
+
+// deliveries returns pending deliveries of events to webhooks of their tenants subscribed to their types
+func (dispatcher *Dispatcher) deliveries(webhooks []*repository.Webhook, events []*repository.Event) ([]*repository.Delivery, error) {
+	var deliveries []*repository.Delivery
+
+	now := time.NowUTC()
+
+	for _, event := range events {
+		var payload []byte
+
+		for _, webhook := range webhooks {
+			if webhook.Tenant != event.Tenant || !webhook.Subscribed(event.Type) {
+				continue
+			}
+
+			if payload == nil {
+				message, err := NewMessage(event)
+				if err != nil {
+					return nil, err
+				}
+
+				payload, err = json.Marshal(message)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			nextAttemptAt := now
+
+			deliveries = append(deliveries, &repository.Delivery{
+				WebhookUuid:   webhook.Uuid,
+				Type:          event.Type,
+				Payload:       string(payload),
+				Status:        repository.PendingDelivery,
+				NextAttemptAt: &nextAttemptAt,
+			})
+		}
+	}
+
+	return deliveries, nil
+}
+
+// dispatch attempting one batch of due deliveries, returns true if the next batch may be ready
+func (dispatcher *Dispatcher) dispatch(ctx context.Context) bool {
+	ctx, span := dispatcher.tracer.Start(ctx, "dispatch")
+	defer span.End()
+
+	deliveries, err := dispatcher.repository.DueDeliveries(ctx, time.NowUTC(), dispatcher.config.BatchSize)
+	if err != nil {
+		dispatcher.logger.Error(err)
+		return false
+	}
+
+	span.SetAttributes(attribute.Int("count", len(deliveries)))
+
+	// due deliveries belong to webhooks of every tenant, webhooks missing in the map were removed
+	var webhooks map[uuid.UUID]*repository.Webhook
+
+	for _, delivery := range deliveries {
+		// claim outlives attempt limited by timeout of request, so nobody else attempts the delivery meanwhile
+		now := time.NowUTC()
+
+		claimed, err := dispatcher.repository.ClaimDelivery(ctx, delivery, now, now.Add(2*dispatcher.config.Timeout))
+		if err != nil {
+			dispatcher.logger.Error(err)
+			return false
+		}
+
+		if !claimed {
+			continue
+		}
+
+		if webhooks == nil {
+			all, err := dispatcher.repository.AllWebhooks(ctx)
+			if err != nil {
+				dispatcher.logger.Error(err)
+				return false
+			}
+
+			webhooks = make(map[uuid.UUID]*repository.Webhook, len(all))
+			for _, webhook := range all {
+				webhooks[webhook.Uuid] = webhook
+			}
+		}
+
+		webhook := webhooks[delivery.WebhookUuid]
+
+		dispatcher.attempt(ctx, webhook, delivery)
+
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if err := dispatcher.repository.UpdateDelivery(ctx, delivery); err != nil {
+			dispatcher.logger.Error(err)
+			return false
+		}
+	}
+
+	return uint(len(deliveries)) == dispatcher.config.BatchSize
+}
+
+// attempt sending delivery to webhook and recording outcome of the attempt to delivery,
+// nil webhook was removed after scheduling of delivery
+func (dispatcher *Dispatcher) attempt(ctx context.Context, webhook *repository.Webhook, delivery *repository.Delivery) {
+	delivery.Attempts++
+
+	var err error
+
+	switch {
+	case webhook == nil:
+		delivery.ResponseStatus, err = 0, fmt.Errorf("webhook: %s removed", delivery.WebhookUuid.String())
+		delivery.Attempts = dispatcher.config.MaxAttempts
+	case !webhook.Active:
+		delivery.ResponseStatus, err = 0, fmt.Errorf("webhook: %s inactive", delivery.WebhookUuid.String())
+		delivery.Attempts = dispatcher.config.MaxAttempts
+	default:
+		delivery.ResponseStatus, err = dispatcher.send(ctx, webhook, delivery)
+	}
+
+	delivery.NextAttemptAt = nil
+
+	if err == nil {
+		delivery.Status = repository.DeliveredDelivery
+		delivery.Error = ""
+
+		dispatcher.logger.Infof("webhook: delivered %d of '%s' to '%s'", delivery.Id, delivery.Type, webhook.Url)
+
+		return
+	}
+
+	delivery.Error = err.Error()
+	if len(delivery.Error) > maxErrorLength {
+		delivery.Error = delivery.Error[:maxErrorLength]
+	}
+
+	if delivery.Attempts >= dispatcher.config.MaxAttempts {
+		delivery.Status = repository.FailedDelivery
+
+		dispatcher.logger.Errorf("webhook: delivery %d failed after %d attempts: %s", delivery.Id, delivery.Attempts, delivery.Error)
+
+		return
+	}
+
+	nextAttemptAt := time.NowUTC().Add(dispatcher.backoff(delivery.Attempts))
+	delivery.NextAttemptAt = &nextAttemptAt
+
+	dispatcher.logger.Errorf("webhook: delivery %d attempt %d failed, next at %s: %s", delivery.Id, delivery.Attempts, nextAttemptAt, delivery.Error)
+}
+
+// backoff returns delay after attempt of delivery: Config.Backoff doubled by every attempt after the first one
+func (dispatcher *Dispatcher) backoff(attempts int) stdTime.Duration {
+	backoff := dispatcher.config.Backoff
+
+	for attempt := 1; attempt < attempts && backoff < dispatcher.config.MaxBackoff; attempt++ {
+		backoff *= 2
+	}
+
+	if backoff > dispatcher.config.MaxBackoff {
+		backoff = dispatcher.config.MaxBackoff
+	}
+
+	return backoff
+}
+
+// send posting payload of delivery signed by secret of webhook, returns status of response,
+// any status except 2xx is failure
+func (dispatcher *Dispatcher) send(ctx context.Context, webhook *repository.Webhook, delivery *repository.Delivery) (int, error) {
+	ctx, span := dispatcher.tracer.Start(ctx, "send")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("delivery", delivery.Id),
+		attribute.Int("attempt", delivery.Attempts),
+		attribute.String("url", webhook.Url),
+	)
+
+	body := []byte(delivery.Payload)
+	timestamp := time.NowUTC().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set(headers.ContentType, mimetype.ApplicationJSON)
+	request.Header.Set(DeliveryHeaderName, strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set(EventHeaderName, delivery.Type)
+	request.Header.Set(TimestampHeaderName, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeaderName, Sign(webhook.Secret, timestamp, body))
+
+	response, err := dispatcher.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+
+	span.SetAttributes(attribute.Int("status", response.StatusCode))
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, fmt.Errorf("webhook: %s responded with status %d", webhook.Url, response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
@@ -0,0 +1,322 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
+	"go.opentelemetry.io/otel/trace"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	stdTime "time"
+)
+
+const testSecret = "secret"
+
+var testTracer = trace.NewNoopTracerProvider().Tracer("test")
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+// receiver webhook of test failing the first attempt of every delivery
+type receiver struct {
+	t *testing.T
+
+	mutex     sync.Mutex
+	attempts  map[string]int
+	delivered []*Message
+}
+
+func (receiver *receiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		receiver.t.Error(err)
+		return
+	}
+
+	timestamp, err := strconv.ParseInt(request.Header.Get(TimestampHeaderName), 10, 64)
+	if err != nil {
+		receiver.t.Error(err)
+		return
+	}
+
+	if !Verify(testSecret, timestamp, body, request.Header.Get(SignatureHeaderName)) {
+		receiver.t.Errorf("invalid signature of delivery %s", request.Header.Get(DeliveryHeaderName))
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	message := &Message{}
+	if err := json.Unmarshal(body, message); err != nil {
+		receiver.t.Error(err)
+		return
+	}
+
+	if message.Type != request.Header.Get(EventHeaderName) {
+		receiver.t.Errorf("type of message '%s', event header '%s'", message.Type, request.Header.Get(EventHeaderName))
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	delivery := request.Header.Get(DeliveryHeaderName)
+
+	receiver.attempts[delivery]++
+	if receiver.attempts[delivery] == 1 {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	receiver.delivered = append(receiver.delivered, message)
+}
+
+// types returns types of delivered messages sorted by type
+func (receiver *receiver) types() []string {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	types := make([]string, len(receiver.delivered))
+	for index, message := range receiver.delivered {
+		types[index] = message.Type
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// newTestDispatcher returns dispatcher of memory repository with webhook of receiver subscribed to events
+func newTestDispatcher(t *testing.T, events ...string) (*Dispatcher, repository.Repository, *receiver) {
+	receiver := &receiver{t: t, attempts: map[string]int{}}
+
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	memory := repository.NewMemory(testTracer)
+
+	_, err := memory.AddWebhook(context.Background(), &repository.Webhook{
+		Url:    server.URL,
+		Secret: testSecret,
+		Events: repository.JoinEvents(events),
+		Active: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		Timeout:     stdTime.Second,
+		MaxAttempts: 3,
+		Backoff:     stdTime.Millisecond,
+		MaxBackoff:  stdTime.Millisecond,
+		Interval:    stdTime.Millisecond,
+		BatchSize:   2,
+	}
+
+	return NewDispatcher(config, memory, server.Client(), testTracer, testLogger{t}), memory, receiver
+}
+
+// pending returns count of pending deliveries
+func pending(t *testing.T, memory repository.Repository) int {
+	deliveries, err := memory.DueDeliveries(context.Background(), time.NowUTC().Add(stdTime.Hour), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(deliveries)
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	dispatcher, memory, receiver := newTestDispatcher(t)
+	ctx := context.Background()
+
+	login, err := memory.Insert(ctx, &repository.Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unban of login which isn't banned isn't delivered
+	if _, err := memory.UnbanByUuid(ctx, login.Uuid, &repository.Ban{Actor: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expired := time.NowUTC().Add(-stdTime.Minute)
+
+	if _, err := memory.BanByUuid(ctx, login.Uuid, &repository.Ban{Actor: "admin", Until: &expired, Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := memory.UnbanExpired(ctx, time.NowUTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := memory.InsertBatch(ctx, []*repository.Login{{Login: "bob"}, {Login: "carol"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for dispatcher.schedule(ctx) {
+	}
+
+	if count := pending(t, memory); count != 5 {
+		t.Fatalf("scheduled deliveries %d, expected 5", count)
+	}
+
+	for attempt := 0; attempt < 100 && pending(t, memory) > 0; attempt++ {
+		for dispatcher.dispatch(ctx) {
+		}
+
+		stdTime.Sleep(stdTime.Millisecond)
+	}
+
+	types := receiver.types()
+	expected := []string{CreatedEvent, CreatedEvent, CreatedEvent, BannedEvent, UnbannedEvent}
+	sort.Strings(expected)
+
+	if len(types) != len(expected) {
+		t.Fatalf("delivered %v, expected %v", types, expected)
+	}
+
+	for index := range types {
+		if types[index] != expected[index] {
+			t.Fatalf("delivered %v, expected %v", types, expected)
+		}
+	}
+
+	// events are scheduled once
+	if dispatcher.schedule(ctx); pending(t, memory) != 0 {
+		t.Fatal("events are scheduled again")
+	}
+}
+
+func TestDispatcher_Subscribed(t *testing.T) {
+	dispatcher, memory, _ := newTestDispatcher(t, BannedEvent)
+	ctx := context.Background()
+
+	login, err := memory.Insert(ctx, &repository.Login{Login: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := memory.BanByUuid(ctx, login.Uuid, &repository.Ban{Actor: "admin", Reason: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for dispatcher.schedule(ctx) {
+	}
+
+	deliveries, err := memory.DueDeliveries(ctx, time.NowUTC(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deliveries) != 1 || deliveries[0].Type != BannedEvent {
+		t.Fatalf("scheduled deliveries %d, expected the one of ban", len(deliveries))
+	}
+}
+
+// racing repository whose due deliveries are claimed by another instance right after they are read
+type racing struct {
+	repository.Repository
+}
+
+func (racing *racing) DueDeliveries(ctx context.Context, now stdTime.Time, limit uint) ([]*repository.Delivery, error) {
+	deliveries, err := racing.Repository.DueDeliveries(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		if _, err := racing.Repository.ClaimDelivery(ctx, delivery, now, now.Add(stdTime.Minute)); err != nil {
+			return nil, err
+		}
+	}
+
+	return deliveries, nil
+}
+
+func TestDispatcher_Claimed(t *testing.T) {
+	dispatcher, memory, receiver := newTestDispatcher(t)
+	ctx := context.Background()
+
+	if _, err := memory.Insert(ctx, &repository.Login{Login: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for dispatcher.schedule(ctx) {
+	}
+
+	dispatcher.repository = &racing{Repository: memory}
+
+	for dispatcher.dispatch(ctx) {
+	}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if len(receiver.attempts) != 0 {
+		t.Fatalf("attempts of delivery claimed by another instance %v, expected none", receiver.attempts)
+	}
+
+	if count := pending(t, memory); count != 1 {
+		t.Fatalf("pending deliveries %d, expected 1", count)
+	}
+}
+
+func TestDispatcher_Tenant(t *testing.T) {
+	dispatcher, memory, _ := newTestDispatcher(t)
+	ctx := context.Background()
+	acme := repository.WithTenant(ctx, "acme")
+
+	webhook, err := memory.AddWebhook(acme, &repository.Webhook{Url: "http://localhost/", Secret: testSecret, Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := memory.Insert(ctx, &repository.Login{Login: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := memory.Insert(acme, &repository.Login{Login: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for dispatcher.schedule(ctx) {
+	}
+
+	deliveries, err := memory.DueDeliveries(ctx, time.NowUTC(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deliveries) != 2 {
+		t.Fatalf("scheduled deliveries %d, expected one of every tenant", len(deliveries))
+	}
+
+	for _, delivery := range deliveries {
+		message := &Message{}
+		if err := json.Unmarshal([]byte(delivery.Payload), message); err != nil {
+			t.Fatal(err)
+		}
+
+		if (delivery.WebhookUuid == webhook.Uuid) != (message.Login.Tenant == "acme") {
+			t.Fatalf("login of tenant '%s' is delivered to webhook of another tenant", message.Login.Tenant)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// types of events delivered to webhooks
+const (
+	CreatedEvent  = repository.LoginCreatedEvent
+	UpdatedEvent  = repository.LoginUpdatedEvent
+	BannedEvent   = repository.LoginBannedEvent
+	UnbannedEvent = repository.LoginUnbannedEvent
+)
+
+// EventTypes every type of events delivered to webhooks
+var EventTypes = []string{CreatedEvent, UpdatedEvent, BannedEvent, UnbannedEvent}
+
+// InvalidEventError returned when webhook is subscribed to unknown type of events
+var InvalidEventError = errors.New("invalid event type")
+
+// Message body of request to webhook, the same for every attempt of delivery
+type Message struct {
+	Id        uuid.UUID              `json:"id"`
+	Type      string                 `json:"type"`
+	Login     *repository.EventLogin `json:"login"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// NewMessage returns message of event of outbox
+func NewMessage(event *repository.Event) (*Message, error) {
+	login := &repository.EventLogin{}
+	if err := json.Unmarshal([]byte(event.Payload), login); err != nil {
+		return nil, err
+	}
+
+	message := &Message{Id: uuid.New(), Type: event.Type, Login: login, CreatedAt: time.Now().UTC()}
+	if event.CreatedAt != nil {
+		message.CreatedAt = *event.CreatedAt
+	}
+
+	return message, nil
+}
+
+// CheckEvents returns InvalidEventError if any of types is unknown
+func CheckEvents(types []string) error {
+	for _, eventType := range types {
+		if !known(eventType) {
+			return fmt.Errorf("%w: %s", InvalidEventError, eventType)
+		}
+	}
+
+	return nil
+}
+
+func known(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	DeliveryHeaderName  = "X-Webhook-Delivery"
+	EventHeaderName     = "X-Webhook-Event"
+	TimestampHeaderName = "X-Webhook-Timestamp"
+	SignatureHeaderName = "X-Webhook-Signature"
+
+	// signaturePrefix prefix of value of SignatureHeaderName naming the algorithm
+	signaturePrefix = "sha256="
+	// secretSize size of generated secret in bytes
+	secretSize = 32
+)
+
+// Sign returns value of SignatureHeaderName: hex encoded HMAC-SHA256 by secret of unix timestamp
+// of request and its body joined by dot, the timestamp lets receivers reject replayed requests
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is Sign of timestamp and body by secret
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret returns random hex encoded secret of webhook
+func NewSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/Diez37/logins/infrastructure/migrator"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/closer"
+	"github.com/diez37/go-packages/container"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// FormatFieldName name of flag with format of export
+	FormatFieldName = "format"
+
+	// OutputFieldName name of flag with path to file of export
+	OutputFieldName = "output"
+
+	// StdoutFile value of output flag for writing to stdout
+	StdoutFile = "-"
+)
+
+// NewExportCommand creating, configuration and return cobra.Command for export of logins
+func NewExportCommand(container container.Container) (*cobra.Command, error) {
+	var format, output, tenant string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export every login as newline delimited json or csv",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := exporter.CheckFormat(format); err != nil {
+				return err
+			}
+
+			if err := repository.CheckTenant(tenant); err != nil {
+				return err
+			}
+
+			return container.Invoke(func(
+				closer closer.Closer,
+				migrator migrator.Migrator,
+				loginsExporter *exporter.Exporter,
+			) error {
+				if err := migrator.Up(); err != nil && err != migrate.ErrNoChange {
+					return err
+				}
+
+				var writer io.Writer = cmd.OutOrStdout()
+				if output != StdoutFile {
+					file, err := os.Create(output)
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+
+					writer = file
+				}
+
+				_, err := loginsExporter.Export(repository.WithTenant(closer.GetContext(), tenant), writer, format, &repository.Filter{})
+
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, FormatFieldName, exporter.NDJSONFormat, fmt.Sprintf(
+		"format of export, available values (%s)",
+		strings.Join([]string{exporter.NDJSONFormat, exporter.CSVFormat}, ", "),
+	))
+	cmd.Flags().StringVar(&output, OutputFieldName, StdoutFile, "path to file of export, \"-\" for stdout")
+	cmd.Flags().StringVar(&tenant, TenantFieldName, repository.DefaultTenant, "tenant of exported logins")
+
+	return cmd, nil
+}
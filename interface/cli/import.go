@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/Diez37/logins/infrastructure/migrator"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/closer"
+	"github.com/diez37/go-packages/container"
+	"github.com/diez37/go-packages/log"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+)
+
+const (
+	// FileFieldName name of flag with path to file of import
+	FileFieldName = "file"
+
+	// TenantFieldName name of flag with tenant of imported or exported logins
+	TenantFieldName = "tenant"
+
+	// StdinFile value of file flag for reading from stdin
+	StdinFile = "-"
+)
+
+// NewImportCommand creating, configuration and return cobra.Command for import of logins
+func NewImportCommand(container container.Container) (*cobra.Command, error) {
+	var file, tenant string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "import logins from json array or newline delimited json file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := repository.CheckTenant(tenant); err != nil {
+				return err
+			}
+
+			return container.Invoke(func(
+				logger log.Logger,
+				closer closer.Closer,
+				migrator migrator.Migrator,
+				loginsImporter *importer.Importer,
+			) error {
+				if err := migrator.Up(); err != nil && err != migrate.ErrNoChange {
+					return err
+				}
+
+				var reader io.Reader = cmd.InOrStdin()
+				if file != StdinFile {
+					input, err := os.Open(file)
+					if err != nil {
+						return err
+					}
+					defer input.Close()
+
+					reader = input
+				}
+
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+
+				_, err := loginsImporter.Import(repository.WithTenant(closer.GetContext(), tenant), reader, func(results []*importer.Result) error {
+					for _, result := range results {
+						if err := encoder.Encode(result); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				})
+
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&file, FileFieldName, "", "path to json array or newline delimited json file of logins, \"-\" for stdin")
+	cmd.Flags().StringVar(&tenant, TenantFieldName, repository.DefaultTenant, "tenant of imported logins")
+	if err := cmd.MarkFlagRequired(FileFieldName); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
@@ -1,9 +1,23 @@
 package cli
 
 import (
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/changes"
 	container2 "github.com/Diez37/logins/infrastructure/container"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/Diez37/logins/infrastructure/database/postgres"
+	"github.com/Diez37/logins/infrastructure/expirer"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/Diez37/logins/infrastructure/metadata"
+	"github.com/Diez37/logins/infrastructure/migrator"
+	"github.com/Diez37/logins/infrastructure/outbox"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/reserved"
+	"github.com/Diez37/logins/infrastructure/webhook"
 	"github.com/Diez37/logins/interface/http"
 	"github.com/diez37/go-packages/app"
+	"github.com/diez37/go-packages/clients/db"
 	"github.com/diez37/go-packages/closer"
 	"github.com/diez37/go-packages/configurator"
 	bindFlags "github.com/diez37/go-packages/configurator/bind_flags"
@@ -11,6 +25,7 @@ import (
 	"github.com/diez37/go-packages/log"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
 const (
@@ -27,13 +42,19 @@ func NewRootCommand() (*cobra.Command, error) {
 	}
 
 	cmd := &cobra.Command{
-		PreRunE: func(cmd *cobra.Command, _ []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 			return container.Invoke(func(generalConfig *app.Config, configurator configurator.Configurator) {
 				app.Configuration(generalConfig, configurator, app.WithAppName(AppName))
 			})
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return container.Invoke(func(generalConfig *app.Config, logger log.Logger, closer closer.Closer, migrator *migrate.Migrate) error {
+			return container.Invoke(func(
+				generalConfig *app.Config,
+				logger log.Logger,
+				closer closer.Closer,
+				migrator migrator.Migrator,
+				seeder *reserved.Seeder,
+			) error {
 				logger.Infof("app: %s started", generalConfig.Name)
 				logger.Infof("app: pid - %d", generalConfig.PID)
 
@@ -41,6 +62,10 @@ func NewRootCommand() (*cobra.Command, error) {
 					return err
 				}
 
+				if err := seeder.Seed(closer.GetContext()); err != nil {
+					return err
+				}
+
 				return http.Serve(closer.GetContext(), container, logger)
 			})
 		},
@@ -56,5 +81,78 @@ func NewRootCommand() (*cobra.Command, error) {
 		return nil, err
 	}
 
+	err = container.Invoke(func(
+		postgresConfig *postgres.Config,
+		dbConfig *db.Config,
+		expirerConfig *expirer.Config,
+		reservedConfig *reserved.Config,
+		aliasConfig *alias.Config,
+		metadataConfig *metadata.Config,
+		importerConfig *importer.Config,
+		cacheConfig *repository.CacheConfig,
+		replicasConfig *database.ReplicasConfig,
+		outboxConfig *outbox.Config,
+		webhookConfig *webhook.Config,
+		changesConfig *changes.Config,
+	) {
+		cmd.PersistentFlags().StringVar(&postgresConfig.Host, postgres.HostFieldName, postgres.HostDefault, "")
+		cmd.PersistentFlags().Uint32Var(&postgresConfig.Port, postgres.PortFieldName, postgres.PortDefault, "")
+		cmd.PersistentFlags().StringVar(&postgresConfig.User, postgres.UserFieldName, "", "")
+		cmd.PersistentFlags().StringVar(&postgresConfig.Password, postgres.PasswordFieldName, "", "")
+		cmd.PersistentFlags().StringVar(&postgresConfig.DataBase, postgres.DataBaseFieldName, "", "")
+		cmd.PersistentFlags().StringVar(&postgresConfig.SSLMode, postgres.SSLModeFieldName, postgres.SSLModeDefault, "")
+
+		cmd.PersistentFlags().Lookup(db.DriverFieldName).Usage = fmt.Sprintf(
+			"type db usage, available values (%s)",
+			strings.Join([]string{db.MySQLDriver, db.SQLiteDriver, database.PostgresDriver, database.MemoryDriver}, ", "),
+		)
+
+		cmd.PersistentFlags().StringSliceVar(&replicasConfig.DSNs, database.ReplicasFieldName, nil, "dsn of read replica opened by the db driver, reads of logins are sent to replicas")
+		cmd.PersistentFlags().DurationVar(&replicasConfig.ReadYourWrites, database.ReadYourWritesFieldName, database.ReadYourWritesDefault, "period after write while reads of the same session are sent to primary, 0 disables pinning")
+		cmd.PersistentFlags().BoolVar(&replicasConfig.ReadYourWritesByAddress, database.ReadYourWritesByAddressFieldName, database.ReadYourWritesByAddressDefault, "address of client is session of requests without session header for read your writes")
+
+		cmd.PersistentFlags().DurationVar(&expirerConfig.Interval, expirer.IntervalFieldName, expirer.IntervalDefault, "interval between checks of expired bans")
+		cmd.PersistentFlags().StringVar(&reservedConfig.Seed, reserved.SeedFieldName, reserved.SeedDefault, "path to file of reserved logins")
+		cmd.PersistentFlags().DurationVar(&aliasConfig.Cooldown, alias.CooldownFieldName, alias.CooldownDefault, "period after rename while former login can't be claimed by another login")
+		cmd.PersistentFlags().IntVar(&metadataConfig.MaxSize, metadata.MaxSizeFieldName, metadata.MaxSizeDefault, "max size of metadata of login in bytes of its compact json")
+		cmd.PersistentFlags().IntVar(&cacheConfig.Size, repository.CacheSizeFieldName, repository.CacheSizeDefault, "max count of cached lookups of logins by uuid and login, 0 disables cache")
+		cmd.PersistentFlags().DurationVar(&cacheConfig.TTL, repository.CacheTTLFieldName, repository.CacheTTLDefault, "time of life of cached lookup of login")
+		cmd.PersistentFlags().StringVar(&outboxConfig.Publisher, outbox.PublisherFieldName, outbox.PublisherDefault, fmt.Sprintf(
+			"publisher of login change events, available values (%s), empty disables publishing",
+			strings.Join([]string{outbox.FilePublisherName, outbox.HTTPPublisherName}, ", "),
+		))
+		cmd.PersistentFlags().StringVar(&outboxConfig.File, outbox.FileFieldName, outbox.FileDefault, "newline delimited json file of file publisher, \"-\" for stdout")
+		cmd.PersistentFlags().StringVar(&outboxConfig.HTTPURL, outbox.HTTPURLFieldName, "", "url receiving events by POST requests of http publisher")
+		cmd.PersistentFlags().DurationVar(&outboxConfig.HTTPTimeout, outbox.HTTPTimeoutFieldName, outbox.HTTPTimeoutDefault, "timeout of request of http publisher")
+		cmd.PersistentFlags().DurationVar(&outboxConfig.Interval, outbox.IntervalFieldName, outbox.IntervalDefault, "interval between checks of unpublished events")
+		cmd.PersistentFlags().UintVar(&outboxConfig.BatchSize, outbox.BatchSizeFieldName, outbox.BatchSizeDefault, "max count of events published by one check")
+		cmd.PersistentFlags().DurationVar(&webhookConfig.Timeout, webhook.TimeoutFieldName, webhook.TimeoutDefault, "timeout of request to webhook")
+		cmd.PersistentFlags().IntVar(&webhookConfig.MaxAttempts, webhook.MaxAttemptsFieldName, webhook.MaxAttemptsDefault, "count of attempts of delivery to webhook before it's failed")
+		cmd.PersistentFlags().DurationVar(&webhookConfig.Backoff, webhook.BackoffFieldName, webhook.BackoffDefault, "delay before the second attempt of delivery to webhook, doubled by every next attempt")
+		cmd.PersistentFlags().DurationVar(&webhookConfig.MaxBackoff, webhook.MaxBackoffFieldName, webhook.MaxBackoffDefault, "max delay between attempts of delivery to webhook")
+		cmd.PersistentFlags().DurationVar(&webhookConfig.Interval, webhook.IntervalFieldName, webhook.IntervalDefault, "interval between checks of due deliveries to webhooks")
+		cmd.PersistentFlags().UintVar(&webhookConfig.BatchSize, webhook.BatchSizeFieldName, webhook.BatchSizeDefault, "max count of deliveries to webhooks attempted by one check")
+		cmd.PersistentFlags().DurationVar(&changesConfig.Interval, changes.IntervalFieldName, changes.IntervalDefault, "interval between checks of new changes by every stream of changes")
+		cmd.PersistentFlags().DurationVar(&changesConfig.Heartbeat, changes.HeartbeatFieldName, changes.HeartbeatDefault, "period without changes after which stream of changes sends heartbeat")
+		cmd.PersistentFlags().UintVar(&changesConfig.BatchSize, changes.BatchSizeFieldName, changes.BatchSizeDefault, "max count of changes read by one check of stream of changes")
+		cmd.PersistentFlags().UintVar(&importerConfig.ChunkSize, importer.ChunkSizeFieldName, importer.ChunkSizeDefault, "count of rows inserted by one transaction of import")
+		cmd.PersistentFlags().UintVar(&importerConfig.MaxBatchRows, importer.MaxBatchRowsFieldName, importer.MaxBatchRowsDefault, "max count of rows of one request of batch create")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	importCmd, err := NewImportCommand(container)
+	if err != nil {
+		return nil, err
+	}
+
+	exportCmd, err := NewExportCommand(container)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.AddCommand(importCmd, exportCmd)
+
 	return cmd, nil
 }
@@ -2,6 +2,13 @@ package api
 
 import (
 	"fmt"
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/changes"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/Diez37/logins/infrastructure/metadata"
+	"github.com/Diez37/logins/infrastructure/policy"
 	"github.com/Diez37/logins/infrastructure/repository"
 	v1 "github.com/Diez37/logins/interface/http/api/v1"
 	"github.com/diez37/go-packages/log"
@@ -9,48 +16,257 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"go.opentelemetry.io/otel/trace"
+	"net"
+	"net/http"
 )
 
-func Router(repository repository.Repository, tracer trace.Tracer, logger log.Logger, validator *validator.Validate) chi.Router {
-	apiV1 := v1.NewAPI(repository, tracer, logger, validator)
+func Router(
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+	validator *validator.Validate,
+	policy *policy.LoginPolicy,
+	cooldown *alias.Cooldown,
+	limiter *metadata.Limiter,
+	importer *importer.Importer,
+	exporter *exporter.Exporter,
+	feed *changes.Feed,
+	replicasConfig *database.ReplicasConfig,
+) chi.Router {
+	apiV1 := v1.NewAPI(repository, tracer, logger, validator, policy, cooldown, limiter, importer, exporter, feed)
 
 	router := chi.NewRouter()
+	router.Use(session(replicasConfig.ReadYourWritesByAddress))
 
 	router.Route("/v1", func(r chi.Router) {
-		r.Put("/login", apiV1.Add)
+		r.Use(tenant(logger, func(request *http.Request) string {
+			return request.Header.Get(v1.TenantHeaderName)
+		}))
 
-		r.Route(fmt.Sprintf("/uuid/{%s}", v1.UuidFieldName), func(r chi.Router) {
-			r.Use(middlewares.NewUUID(logger, middlewares.WithName(v1.UuidFieldName), middlewares.WithUri(v1.UuidFieldName)).Middleware)
-			r.Get("/", apiV1.FindByUuid)
-			r.Delete("/", apiV1.BanByUuid)
-			r.Post("/", apiV1.UpdateByUuid)
+		logins(r, apiV1, logger)
+
+		r.Route("/reserved", func(r chi.Router) {
+			r.Get("/", apiV1.ListReserved)
+			r.Put("/", apiV1.AddReserved)
+			r.With(
+				middlewares.NewString(logger, middlewares.WithName(v1.PatternFieldName), middlewares.WithUri(v1.PatternFieldName)).Middleware,
+			).Delete(fmt.Sprintf("/{%s}", v1.PatternFieldName), apiV1.RemoveReserved)
 		})
 
-		r.Route(fmt.Sprintf("/login/{%s}", v1.LoginFieldName), func(r chi.Router) {
-			r.Use(middlewares.NewString(logger, middlewares.WithName(v1.LoginFieldName), middlewares.WithUri(v1.LoginFieldName)).Middleware)
-			r.Get("/", apiV1.FindByLogin)
+		webhooks(r, apiV1, logger)
+
+		r.Route(fmt.Sprintf("/tenants/{%s}", v1.TenantFieldName), func(r chi.Router) {
+			r.Use(tenant(logger, func(request *http.Request) string {
+				return chi.URLParam(request, v1.TenantFieldName)
+			}))
+
+			logins(r, apiV1, logger)
+			webhooks(r, apiV1, logger)
 		})
+	})
 
-		r.Get("/count", apiV1.Count)
-		r.Route("/logins", func(r chi.Router) {
-			r.Use(middlewares.NewUint64(
-				logger,
-				middlewares.WithName(v1.PageFieldName),
-				middlewares.WithQuery(v1.PageFieldName),
-				middlewares.WithHeader(v1.PageHeaderName),
-				middlewares.WithDefault(v1.PageDefault),
-			).Middleware)
+	return router
+}
+
+// webhooks routes of webhooks of tenant of request
+func webhooks(r chi.Router, apiV1 *v1.API, logger log.Logger) {
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Get("/", apiV1.ListWebhooks)
+		r.Put("/", apiV1.AddWebhook)
+		r.Route(fmt.Sprintf("/{%s}", v1.UuidFieldName), func(r chi.Router) {
+			r.Use(middlewares.NewUUID(logger, middlewares.WithName(v1.UuidFieldName), middlewares.WithUri(v1.UuidFieldName)).Middleware)
+			r.Get("/", apiV1.FindWebhook)
+			r.Post("/", apiV1.UpdateWebhook)
+			r.Delete("/", apiV1.RemoveWebhook)
+			r.With(pagination(logger)...).Get("/deliveries", apiV1.Deliveries)
+		})
+	})
+}
+
+// logins routes of logins of tenant of request
+func logins(r chi.Router, apiV1 *v1.API, logger log.Logger) {
+	r.Put("/login", apiV1.Add)
+
+	r.Route(fmt.Sprintf("/uuid/{%s}", v1.UuidFieldName), func(r chi.Router) {
+		r.Use(middlewares.NewUUID(logger, middlewares.WithName(v1.UuidFieldName), middlewares.WithUri(v1.UuidFieldName)).Middleware)
+		r.Get("/", apiV1.FindByUuid)
+		r.Delete("/", apiV1.BanByUuid)
+		r.Post("/", apiV1.UpdateByUuid)
+		r.Put("/ban", apiV1.Ban)
+		r.Delete("/ban", apiV1.Unban)
+		r.With(pagination(logger)...).Get("/bans", apiV1.Bans)
+	})
+
+	r.Route(fmt.Sprintf("/login/{%s}", v1.LoginFieldName), func(r chi.Router) {
+		r.Use(middlewares.NewString(logger, middlewares.WithName(v1.LoginFieldName), middlewares.WithUri(v1.LoginFieldName)).Middleware)
+		r.Get("/", apiV1.FindByLogin)
+	})
 
-			r.Use(middlewares.NewUint64(
+	r.With(filtering(logger)...).Get("/count", apiV1.Count)
+	r.Post("/logins:batchCreate", apiV1.BatchCreate)
+	r.With(append(
+		filtering(logger),
+		middlewares.NewString(
+			logger,
+			middlewares.WithName(v1.FormatFieldName),
+			middlewares.WithQuery(v1.FormatFieldName),
+			middlewares.WithDefault(v1.FormatDefault),
+		).Middleware,
+	)...).Get("/logins:export", apiV1.Export)
+	r.With(
+		middlewares.NewString(
+			logger,
+			middlewares.WithName(v1.LastEventIdFieldName),
+			middlewares.WithHeader(v1.LastEventIdHeaderName),
+			middlewares.WithQuery(v1.LastEventIdFieldName),
+			middlewares.WithDefault(v1.LastEventIdDefault),
+		).Middleware,
+	).Get("/logins/changes", apiV1.Changes)
+	r.Route("/logins", func(r chi.Router) {
+		r.Use(pagination(logger)...)
+		r.With(append(
+			filtering(logger),
+			middlewares.NewString(
+				logger,
+				middlewares.WithName(v1.SinceRevisionFieldName),
+				middlewares.WithQuery(v1.SinceRevisionFieldName),
+				middlewares.WithDefault(v1.SinceRevisionDefault),
+			).Middleware,
+		)...).Get("/", apiV1.Page)
+		r.With(
+			middlewares.NewString(
+				logger,
+				middlewares.WithName(v1.QueryFieldName),
+				middlewares.WithQuery(v1.QueryFieldName),
+				middlewares.WithDefault(v1.QueryDefault),
+			).Middleware,
+			middlewares.NewString(
 				logger,
-				middlewares.WithName(v1.LimitFieldName),
-				middlewares.WithQuery(v1.LimitFieldName),
-				middlewares.WithHeader(v1.PageHeaderName),
-				middlewares.WithDefault(v1.LimitDefault),
-			).Middleware)
-			r.Get("/", apiV1.Page)
+				middlewares.WithName(v1.ModeFieldName),
+				middlewares.WithQuery(v1.ModeFieldName),
+				middlewares.WithDefault(v1.ModeDefault),
+			).Middleware,
+		).Get("/search", apiV1.Search)
+	})
+}
+
+// pagination middlewares placing page, limit and cursor of request to context
+func pagination(logger log.Logger) chi.Middlewares {
+	return chi.Middlewares{
+		middlewares.NewUint64(
+			logger,
+			middlewares.WithName(v1.PageFieldName),
+			middlewares.WithQuery(v1.PageFieldName),
+			middlewares.WithHeader(v1.PageHeaderName),
+			middlewares.WithDefault(v1.PageDefault),
+		).Middleware,
+		middlewares.NewUint64(
+			logger,
+			middlewares.WithName(v1.LimitFieldName),
+			middlewares.WithQuery(v1.LimitFieldName),
+			middlewares.WithHeader(v1.LimitHeaderName),
+			middlewares.WithDefault(v1.LimitDefault),
+		).Middleware,
+		middlewares.NewString(
+			logger,
+			middlewares.WithName(v1.CursorFieldName),
+			middlewares.WithQuery(v1.CursorFieldName),
+			middlewares.WithDefault(v1.CursorDefault),
+		).Middleware,
+	}
+}
+
+// filtering middlewares placing filter and sort of logins listing to context
+func filtering(logger log.Logger) chi.Middlewares {
+	filters := chi.Middlewares{}
+
+	for _, name := range []string{
+		v1.BannedFieldName,
+		v1.CreatedFromFieldName,
+		v1.CreatedToFieldName,
+		v1.UpdatedFromFieldName,
+		v1.UpdatedToFieldName,
+		v1.LoginPrefixFieldName,
+		v1.MetadataFieldName,
+	} {
+		filters = append(filters, middlewares.NewString(
+			logger,
+			middlewares.WithName(name),
+			middlewares.WithQuery(name),
+			middlewares.WithDefault(v1.FilterDefault),
+		).Middleware)
+	}
+
+	return append(filters,
+		middlewares.NewString(
+			logger,
+			middlewares.WithName(v1.SortFieldName),
+			middlewares.WithQuery(v1.SortFieldName),
+			middlewares.WithDefault(v1.SortDefault),
+		).Middleware,
+		middlewares.NewString(
+			logger,
+			middlewares.WithName(v1.DirectionFieldName),
+			middlewares.WithQuery(v1.DirectionFieldName),
+			middlewares.WithDefault(v1.DirectionDefault),
+		).Middleware,
+	)
+}
+
+// session placing session of client to context for read-your-writes routing of repository,
+// session is taken from header, or from address of client if byAddress, requests without session are never pinned
+func session(byAddress bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			session := request.Header.Get(v1.SessionHeaderName)
+			if session == "" && byAddress {
+				host, _, err := net.SplitHostPort(request.RemoteAddr)
+				if err != nil {
+					host = request.RemoteAddr
+				}
+
+				session = host
+			}
+
+			if session == "" {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			next.ServeHTTP(writer, request.WithContext(repository.WithSession(request.Context(), session)))
 		})
+	}
+}
+
+// Flushing placing http.Flusher of response writer to context of request for streaming handlers,
+// wraps handler of server, since middlewares of router wrap response writer without forwarding of Flush
+func Flushing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if flusher, ok := writer.(http.Flusher); ok {
+			request = request.WithContext(v1.WithFlusher(request.Context(), flusher))
+		}
+
+		next.ServeHTTP(writer, request)
 	})
+}
 
-	return router
+// tenant placing tenant of request taken by tenantOf to context, requests without tenant belong to repository.DefaultTenant
+func tenant(logger log.Logger, tenantOf func(request *http.Request) string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			name := tenantOf(request)
+			if name == "" {
+				next.ServeHTTP(writer, request)
+				return
+			}
+
+			if err := repository.CheckTenant(name); err != nil {
+				http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				logger.Error(err)
+				return
+			}
+
+			next.ServeHTTP(writer, request.WithContext(repository.WithTenant(request.Context(), name)))
+		})
+	}
 }
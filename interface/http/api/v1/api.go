@@ -1,8 +1,17 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/changes"
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/Diez37/logins/infrastructure/metadata"
+	"github.com/Diez37/logins/infrastructure/policy"
 	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/time"
 	"github.com/diez37/go-packages/clients/db"
 	"github.com/diez37/go-packages/log"
 	"github.com/go-http-utils/headers"
@@ -11,7 +20,6 @@ import (
 	"github.com/ldez/mimetype"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
-	"golang.org/x/sync/errgroup"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -23,10 +31,47 @@ type API struct {
 	tracer     trace.Tracer
 	logger     log.Logger
 	validator  *validator.Validate
+	policy     *policy.LoginPolicy
+	cooldown   *alias.Cooldown
+	limiter    *metadata.Limiter
+	importer   *importer.Importer
+	exporter   *exporter.Exporter
+	feed       *changes.Feed
 }
 
-func NewAPI(repository repository.Repository, tracer trace.Tracer, logger log.Logger, validator *validator.Validate) *API {
-	return &API{repository: repository, tracer: tracer, logger: logger, validator: validator}
+func NewAPI(
+	repository repository.Repository,
+	tracer trace.Tracer,
+	logger log.Logger,
+	validator *validator.Validate,
+	policy *policy.LoginPolicy,
+	cooldown *alias.Cooldown,
+	limiter *metadata.Limiter,
+	importer *importer.Importer,
+	exporter *exporter.Exporter,
+	feed *changes.Feed,
+) *API {
+	return &API{
+		repository: repository,
+		tracer:     tracer,
+		logger:     logger,
+		validator:  validator,
+		policy:     policy,
+		cooldown:   cooldown,
+		limiter:    limiter,
+		importer:   importer,
+		exporter:   exporter,
+		feed:       feed,
+	}
+}
+
+// scoped returns copy of handler using repository scoped by transaction
+func (handler *API) scoped(repository repository.Repository) *API {
+	scoped := *handler
+	scoped.repository = repository
+	scoped.cooldown = handler.cooldown.WithAliases(repository)
+
+	return &scoped
 }
 
 func (handler *API) Add(writer http.ResponseWriter, request *http.Request) {
@@ -52,8 +97,26 @@ func (handler *API) Add(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	if err := handler.validator.Struct(login); err != nil {
-		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	if apiError := handler.validate(&login); apiError != nil {
+		handler.writeError(writer, http.StatusBadRequest, apiError)
+		return
+	}
+
+	apiError, err := handler.checkReserved(ctx, login.Login)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	if apiError != nil {
+		handler.writeError(writer, http.StatusConflict, apiError)
+		return
+	}
+
+	metadata, err := handler.limiter.Normalize(login.Metadata)
+	if err != nil {
+		handler.writeError(writer, http.StatusBadRequest, newMetadataError(err))
 		handler.logger.Error(err)
 		return
 	}
@@ -63,12 +126,47 @@ func (handler *API) Add(writer http.ResponseWriter, request *http.Request) {
 		Login:     login.Login,
 		CreatedAt: login.CreatedAt,
 		UpdateAt:  login.UpdateAt,
+		Metadata:  metadata,
 	}
-	if login.Banned != nil {
-		loginForRepository.Banned = *login.Banned
+	if login.Banned != nil && *login.Banned {
+		loginForRepository.Banned = true
+		loginForRepository.BannedUntil = login.BannedUntil
+		loginForRepository.BanReason = login.BanReason
+	}
+
+	err = handler.repository.Transaction(ctx, func(tx repository.Repository) error {
+		scoped := handler.scoped(tx)
+
+		var err error
+		if loginForRepository, err = scoped.repository.Insert(ctx, loginForRepository); err != nil {
+			return err
+		}
+
+		// checked after the insert, which waits for a concurrent rename holding the login, so alias of the rename is seen
+		apiError, err := scoped.checkCooldown(ctx, login.Login, uuid.Nil)
+		if err != nil {
+			return err
+		}
+
+		if apiError != nil {
+			return &responseError{status: http.StatusConflict, apiError: apiError}
+		}
+
+		return nil
+	})
+
+	response := &responseError{}
+	if errors.As(err, &response) {
+		handler.writeResponseError(writer, response)
+		return
+	}
+
+	if apiError, ok := newDuplicateError(err); ok {
+		handler.writeError(writer, http.StatusConflict, apiError)
+		handler.logger.Error(err)
+		return
 	}
 
-	loginForRepository, err = handler.repository.Insert(ctx, loginForRepository)
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -77,13 +175,7 @@ func (handler *API) Add(writer http.ResponseWriter, request *http.Request) {
 
 	handler.logger.Infof("api:v1:add: login '%s', uuid '%s'", loginForRepository.Login, loginForRepository.Uuid.String())
 
-	content, err := json.Marshal(&Login{
-		Uuid:      loginForRepository.Uuid,
-		Login:     loginForRepository.Login,
-		Banned:    &loginForRepository.Banned,
-		CreatedAt: loginForRepository.CreatedAt,
-		UpdateAt:  loginForRepository.UpdateAt,
-	})
+	content, err := json.Marshal(newLogin(loginForRepository))
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -91,6 +183,7 @@ func (handler *API) Add(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.Header().Set(headers.ETag, etag(loginForRepository))
 	writer.WriteHeader(http.StatusOK)
 
 	if _, err := writer.Write(content); err != nil {
@@ -122,24 +215,103 @@ func (handler *API) UpdateByUuid(writer http.ResponseWriter, request *http.Reque
 		return
 	}
 
-	loginFromRepository, err := handler.repository.FindByUuid(ctx, ctx.Value(UuidFieldName).(uuid.UUID))
-	if err != nil && err != db.RecordNotFoundError {
-		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	if apiError := handler.validate(&login); apiError != nil {
+		handler.writeError(writer, http.StatusBadRequest, apiError)
+		return
+	}
+
+	version, err := ifMatch(request)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
 		handler.logger.Error(err)
 		return
 	}
 
-	if err == db.RecordNotFoundError {
-		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	var loginFromRepository *repository.Login
+
+	err = handler.repository.Transaction(ctx, func(tx repository.Repository) error {
+		scoped := handler.scoped(tx)
+
+		found, err := tx.FindByUuid(ctx, ctx.Value(UuidFieldName).(uuid.UUID))
+		if err == db.RecordNotFoundError {
+			return &responseError{status: http.StatusNotFound}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if version > 0 && found.Version != version {
+			return &responseError{status: http.StatusPreconditionFailed}
+		}
+
+		if repository.Canonical(login.Login) != found.LoginCanonical {
+			apiError, err := scoped.checkReserved(ctx, login.Login)
+			if err != nil {
+				return err
+			}
+
+			if apiError != nil {
+				return &responseError{status: http.StatusConflict, apiError: apiError}
+			}
+
+			apiError, err = scoped.checkCooldown(ctx, login.Login, found.Uuid)
+			if err != nil {
+				return err
+			}
+
+			if apiError != nil {
+				return &responseError{status: http.StatusConflict, apiError: apiError}
+			}
+		}
+
+		found.Login = login.Login
+		if login.Banned != nil {
+			found.Banned = *login.Banned
+			found.BannedUntil = nil
+			found.BanReason = ""
+
+			if *login.Banned {
+				found.BannedUntil = login.BannedUntil
+				found.BanReason = login.BanReason
+			}
+		}
+
+		if login.Metadata != nil {
+			found.Metadata, err = handler.updateMetadata(request, found.Metadata, login.Metadata)
+			if err != nil {
+				return &responseError{status: http.StatusBadRequest, apiError: newMetadataError(err)}
+			}
+		}
+
+		loginFromRepository, err = tx.Update(ctx, found)
+
+		return err
+	})
+
+	response := &responseError{}
+	if errors.As(err, &response) {
+		handler.writeResponseError(writer, response)
 		return
 	}
 
-	loginFromRepository.Login = login.Login
-	if login.Banned != nil {
-		loginFromRepository.Banned = *login.Banned
+	if apiError, ok := newDuplicateError(err); ok {
+		handler.writeError(writer, http.StatusConflict, apiError)
+		handler.logger.Error(err)
+		return
+	}
+
+	if errors.Is(err, repository.VersionConflictError) {
+		status := http.StatusConflict
+		if version > 0 {
+			status = http.StatusPreconditionFailed
+		}
+
+		http.Error(writer, http.StatusText(status), status)
+		handler.logger.Error(err)
+		return
 	}
 
-	loginFromRepository, err = handler.repository.Update(ctx, loginFromRepository)
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -148,13 +320,7 @@ func (handler *API) UpdateByUuid(writer http.ResponseWriter, request *http.Reque
 
 	handler.logger.Infof("api:v1:update: login '%s'", loginFromRepository.Uuid.String())
 
-	content, err := json.Marshal(&Login{
-		Uuid:      loginFromRepository.Uuid,
-		Login:     loginFromRepository.Login,
-		Banned:    &loginFromRepository.Banned,
-		CreatedAt: loginFromRepository.CreatedAt,
-		UpdateAt:  loginFromRepository.UpdateAt,
-	})
+	content, err := json.Marshal(newLogin(loginFromRepository))
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -162,6 +328,7 @@ func (handler *API) UpdateByUuid(writer http.ResponseWriter, request *http.Reque
 	}
 
 	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.Header().Set(headers.ETag, etag(loginFromRepository))
 	writer.WriteHeader(http.StatusOK)
 
 	if _, err := writer.Write(content); err != nil {
@@ -191,13 +358,7 @@ func (handler *API) FindByUuid(writer http.ResponseWriter, request *http.Request
 		return
 	}
 
-	content, err := json.Marshal(&Login{
-		Uuid:      login.Uuid,
-		Login:     login.Login,
-		Banned:    &login.Banned,
-		CreatedAt: login.CreatedAt,
-		UpdateAt:  login.UpdateAt,
-	})
+	content, err := json.Marshal(newLogin(login))
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -205,6 +366,7 @@ func (handler *API) FindByUuid(writer http.ResponseWriter, request *http.Request
 	}
 
 	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.Header().Set(headers.ETag, etag(login))
 	writer.WriteHeader(http.StatusOK)
 
 	if _, err := writer.Write(content); err != nil {
@@ -222,7 +384,20 @@ func (handler *API) BanByUuid(writer http.ResponseWriter, request *http.Request)
 		attribute.String("handler", "api.v1"),
 	)
 
-	_, err := handler.repository.BanByUuid(ctx, ctx.Value(UuidFieldName).(uuid.UUID))
+	version, err := ifMatch(request)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		handler.logger.Error(err)
+		return
+	}
+
+	_, err = handler.repository.BanByUuid(ctx, ctx.Value(UuidFieldName).(uuid.UUID), &repository.Ban{Version: version})
+	if errors.Is(err, repository.VersionConflictError) {
+		http.Error(writer, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		handler.logger.Error(err)
+		return
+	}
+
 	if err != nil && err != db.RecordNotFoundError {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -239,6 +414,123 @@ func (handler *API) BanByUuid(writer http.ResponseWriter, request *http.Request)
 	writer.WriteHeader(http.StatusOK)
 }
 
+func (handler *API) Ban(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Ban")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	handler.setBanned(writer, request.WithContext(ctx), true)
+}
+
+func (handler *API) Unban(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Unban")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	handler.setBanned(writer, request.WithContext(ctx), false)
+}
+
+// setBanned banning or unbanning login with parameters from optional body and writing login with the current state
+func (handler *API) setBanned(writer http.ResponseWriter, request *http.Request, banned bool) {
+	ctx := request.Context()
+	loginUuid := ctx.Value(UuidFieldName).(uuid.UUID)
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	ban := Ban{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &ban); err != nil {
+			http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			handler.logger.Error(err)
+			return
+		}
+	}
+
+	if err := handler.validator.Struct(ban); err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	version, err := ifMatch(request)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		handler.logger.Error(err)
+		return
+	}
+
+	banForRepository := &repository.Ban{Actor: ban.By, Reason: ban.Reason, Version: version}
+
+	if banned {
+		if ban.Until != nil && !ban.Until.After(time.NowUTC()) {
+			http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			handler.logger.Errorf("api:v1:ban: until '%s' already passed", ban.Until.String())
+			return
+		}
+
+		banForRepository.Until = ban.Until
+
+		_, err = handler.repository.BanByUuid(ctx, loginUuid, banForRepository)
+	} else {
+		_, err = handler.repository.UnbanByUuid(ctx, loginUuid, banForRepository)
+	}
+
+	if errors.Is(err, repository.VersionConflictError) {
+		http.Error(writer, http.StatusText(http.StatusPreconditionFailed), http.StatusPreconditionFailed)
+		handler.logger.Error(err)
+		return
+	}
+
+	if err != nil && err != db.RecordNotFoundError {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	if err == db.RecordNotFoundError {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	handler.logger.Infof("api:v1:ban: login '%s', banned '%t'", loginUuid.String(), banned)
+
+	login, err := handler.repository.FindByUuid(ctx, loginUuid)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	content, err := json.Marshal(newLogin(login))
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.Header().Set(headers.ETag, etag(login))
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
+
 func (handler *API) FindByLogin(writer http.ResponseWriter, request *http.Request) {
 	ctx, span := handler.tracer.Start(request.Context(), "FindByLogin")
 	defer span.End()
@@ -249,6 +541,10 @@ func (handler *API) FindByLogin(writer http.ResponseWriter, request *http.Reques
 	)
 
 	login, err := handler.repository.FindByLogin(ctx, ctx.Value(LoginFieldName).(string))
+	if err == db.RecordNotFoundError {
+		login, err = handler.findByAlias(ctx, writer, ctx.Value(LoginFieldName).(string))
+	}
+
 	if err != nil && err != db.RecordNotFoundError {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -260,13 +556,7 @@ func (handler *API) FindByLogin(writer http.ResponseWriter, request *http.Reques
 		return
 	}
 
-	content, err := json.Marshal(&Login{
-		Uuid:      login.Uuid,
-		Login:     login.Login,
-		Banned:    &login.Banned,
-		CreatedAt: login.CreatedAt,
-		UpdateAt:  login.UpdateAt,
-	})
+	content, err := json.Marshal(newLogin(login))
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
@@ -274,6 +564,7 @@ func (handler *API) FindByLogin(writer http.ResponseWriter, request *http.Reques
 	}
 
 	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.Header().Set(headers.ETag, etag(login))
 	writer.WriteHeader(http.StatusOK)
 
 	if _, err := writer.Write(content); err != nil {
@@ -282,6 +573,23 @@ func (handler *API) FindByLogin(writer http.ResponseWriter, request *http.Reques
 	}
 }
 
+// findByAlias returns current login renamed from the login and sets former login to X-Login-Renamed-From header
+func (handler *API) findByAlias(ctx context.Context, writer http.ResponseWriter, login string) (*repository.Login, error) {
+	alias, err := handler.repository.FindAlias(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	loginFromRepository, err := handler.repository.FindByUuid(ctx, alias.LoginUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	writer.Header().Set(RenamedFromHeaderName, alias.Login)
+
+	return loginFromRepository, nil
+}
+
 func (handler *API) Page(writer http.ResponseWriter, request *http.Request) {
 	ctx, span := handler.tracer.Start(request.Context(), "Page")
 	defer span.End()
@@ -291,29 +599,141 @@ func (handler *API) Page(writer http.ResponseWriter, request *http.Request) {
 		attribute.String("handler", "api.v1"),
 	)
 
-	page := ctx.Value(PageFieldName).(uint)
-	limit := ctx.Value(LimitFieldName).(uint)
+	page, limit, cursor, err := pagination(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	if since := ctx.Value(SinceRevisionFieldName).(string); since != "" {
+		if page != 1 || cursor != nil {
+			http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			handler.logger.Error(InvalidRevisionPaginationError)
+			return
+		}
+
+		handler.changedSince(writer, request, since, limit)
+		return
+	}
+
+	loginsFilter, err := filter(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
 
 	var totalCount int64
 	var models []*repository.Login
 
-	wg := &errgroup.Group{}
+	err = handler.repository.Snapshot(ctx, func(tx repository.Repository) error {
+		count, err := tx.Count(ctx, loginsFilter)
+		if err != nil {
+			return err
+		}
 
-	wg.Go(func() error {
-		count, err := handler.repository.Count(ctx)
 		totalCount = count
 
+		if cursor != nil {
+			models, err = tx.PageByCursor(ctx, loginsFilter, cursor, limit)
+		} else {
+			models, err = tx.Page(ctx, loginsFilter, page-1, limit)
+		}
+
 		return err
 	})
+	if errors.Is(err, repository.InvalidCursorError) {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
 
-	wg.Go(func() error {
-		logins, err := handler.repository.Page(ctx, page-1, limit)
-		models = logins
+	if err != nil && err != io.EOF {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
 
-		return err
+	var next string
+	if uint(len(models)) == limit {
+		next = repository.NewCursor(models[len(models)-1], loginsFilter).Encode()
+	}
+
+	logins := make([]*Login, len(models))
+	for index, login := range models {
+		logins[index] = newLogin(login)
+	}
+
+	meta := &Meta{
+		Count: totalCount,
+		Page:  page,
+		Limit: limit,
+		Next:  next,
+	}
+
+	content, err := json.Marshal(&Page{
+		Meta:    meta,
+		Records: logins,
 	})
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	meta.setHeaders(writer.Header())
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
+
+func (handler *API) Search(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Search")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	page, limit, cursor, err := pagination(ctx)
+	if err == nil && cursor != nil {
+		err = InvalidSearchPaginationError
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	search, err := repository.NewSearch(ctx.Value(QueryFieldName).(string), ctx.Value(ModeFieldName).(string))
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	var totalCount int64
+	var models []*repository.Login
+
+	err = handler.repository.Snapshot(ctx, func(tx repository.Repository) error {
+		count, err := tx.CountSearch(ctx, search)
+		if err != nil {
+			return err
+		}
+
+		totalCount = count
+		models, err = tx.Search(ctx, search, page-1, limit)
 
-	if err := wg.Wait(); err != nil && err != io.EOF {
+		return err
+	})
+	if err != nil && err != io.EOF {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
 		return
@@ -321,21 +741,17 @@ func (handler *API) Page(writer http.ResponseWriter, request *http.Request) {
 
 	logins := make([]*Login, len(models))
 	for index, login := range models {
-		logins[index] = &Login{
-			Uuid:      login.Uuid,
-			Login:     login.Login,
-			Banned:    &login.Banned,
-			CreatedAt: login.CreatedAt,
-			UpdateAt:  login.UpdateAt,
-		}
+		logins[index] = newLogin(login)
+	}
+
+	meta := &Meta{
+		Count: totalCount,
+		Page:  page,
+		Limit: limit,
 	}
 
 	content, err := json.Marshal(&Page{
-		Meta: &Meta{
-			Count: totalCount,
-			Page:  page,
-			Limit: limit,
-		},
+		Meta:    meta,
 		Records: logins,
 	})
 	if err != nil {
@@ -345,9 +761,99 @@ func (handler *API) Page(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	writer.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
-	writer.Header().Set(CountHeaderName, strconv.FormatInt(totalCount, 10))
-	writer.Header().Set(PageHeaderName, strconv.FormatUint(uint64(page), 10))
-	writer.Header().Set(LimitHeaderName, strconv.FormatUint(uint64(limit), 10))
+	meta.setHeaders(writer.Header())
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
+
+func (handler *API) Bans(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Bans")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	page, limit, cursor, err := pagination(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	loginUuid := ctx.Value(UuidFieldName).(uuid.UUID)
+
+	_, err = handler.repository.FindByUuid(ctx, loginUuid)
+	if err != nil && err != db.RecordNotFoundError {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	if err == db.RecordNotFoundError {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	var totalCount int64
+	var models []*repository.BanRecord
+
+	err = handler.repository.Snapshot(ctx, func(tx repository.Repository) error {
+		count, err := tx.CountBans(ctx, loginUuid)
+		if err != nil {
+			return err
+		}
+
+		totalCount = count
+
+		if cursor != nil {
+			models, err = tx.PageBansByCursor(ctx, loginUuid, cursor, limit)
+		} else {
+			models, err = tx.PageBans(ctx, loginUuid, page-1, limit)
+		}
+
+		return err
+	})
+	if err != nil && err != io.EOF {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	var next string
+	if uint(len(models)) == limit {
+		next = (&repository.Cursor{Id: models[len(models)-1].Id}).Encode()
+	}
+
+	records := make([]*BanRecord, len(models))
+	for index, record := range models {
+		records[index] = newBanRecord(record)
+	}
+
+	meta := &Meta{
+		Count: totalCount,
+		Page:  page,
+		Limit: limit,
+		Next:  next,
+	}
+
+	content, err := json.Marshal(&BanPage{
+		Meta:    meta,
+		Records: records,
+	})
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	meta.setHeaders(writer.Header())
 	writer.WriteHeader(http.StatusOK)
 
 	if _, err := writer.Write(content); err != nil {
@@ -365,7 +871,14 @@ func (handler *API) Count(writer http.ResponseWriter, request *http.Request) {
 		attribute.String("handler", "api.v1"),
 	)
 
-	count, err := handler.repository.Count(ctx)
+	loginsFilter, err := filter(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	count, err := handler.repository.Count(ctx, loginsFilter)
 	if err != nil {
 		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		handler.logger.Error(err)
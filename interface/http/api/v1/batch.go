@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/go-http-utils/headers"
+	"github.com/ldez/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+	"strings"
+)
+
+// BatchCreate inserting logins from json array or newline delimited json body of up to import.max_batch_rows rows,
+// results of rows are written in the same format as the body once the whole body is read,
+// because http/1 server closes the body as soon as the response is started, so results are kept in memory
+func (handler *API) BatchCreate(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "BatchCreate")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	ndjson := strings.HasPrefix(request.Header.Get(headers.ContentType), NDJSONContentType)
+
+	results := []*importer.Result{}
+
+	summary, err := handler.importer.Batch(ctx, request.Body, func(chunk []*importer.Result) error {
+		results = append(results, chunk...)
+
+		return nil
+	})
+
+	status := http.StatusOK
+	if err != nil {
+		handler.logger.Error(err)
+
+		status = http.StatusInternalServerError
+		switch {
+		case errors.Is(err, importer.InvalidInputError):
+			status = http.StatusBadRequest
+		case errors.Is(err, importer.TooManyRowsError):
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		// results of already inserted chunks are written anyway so client can see what was created
+		if len(results) == 0 {
+			http.Error(writer, http.StatusText(status), status)
+			return
+		}
+	}
+
+	var content []byte
+
+	if ndjson {
+		for _, result := range results {
+			line, err := json.Marshal(result)
+			if err != nil {
+				http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				handler.logger.Error(err)
+				return
+			}
+
+			content = append(append(content, line...), '\n')
+		}
+
+		writer.Header().Set(headers.ContentType, NDJSONContentType)
+	} else {
+		content, err = json.Marshal(results)
+		if err != nil {
+			http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			handler.logger.Error(err)
+			return
+		}
+
+		writer.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	}
+
+	writer.WriteHeader(status)
+
+	if _, err := writer.Write(content); err != nil {
+		handler.logger.Error(err)
+		return
+	}
+
+	if summary != nil {
+		handler.logger.Infof(
+			"api:v1:batch: created %d, duplicate %d, invalid %d",
+			summary.Created,
+			summary.Duplicate,
+			summary.Invalid,
+		)
+	}
+}
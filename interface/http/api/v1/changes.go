@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/outbox"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/go-http-utils/headers"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+	"strconv"
+)
+
+// Changes streaming changes of logins of tenant of request as server-sent events with sequence numbers
+// of the changes as ids, the stream is resumed after Last-Event-ID header or last_event_id query,
+// otherwise it starts from new changes
+func (handler *API) Changes(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Changes")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	flusher, ok := flusherOf(ctx, writer)
+	if !ok {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error("api:v1:changes: response writer doesn't support flushing")
+		return
+	}
+
+	var after int64
+	var err error
+
+	if lastEventId := ctx.Value(LastEventIdFieldName).(string); lastEventId != "" {
+		after, err = strconv.ParseInt(lastEventId, 10, 64)
+		if err == nil && after < 0 {
+			err = fmt.Errorf("api:v1:changes: negative last event id %d", after)
+		}
+
+		if err != nil {
+			http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			handler.logger.Error(err)
+			return
+		}
+	} else {
+		after, err = handler.feed.Last(ctx)
+		if err != nil {
+			http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			handler.logger.Error(err)
+			return
+		}
+	}
+
+	span.SetAttributes(attribute.Int64("after", after))
+
+	writer.Header().Set(headers.ContentType, EventStreamContentType)
+	writer.Header().Set(headers.CacheControl, "no-cache")
+	// disables buffering of proxies, nginx in particular
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	handler.logger.Infof("api:v1:changes: streaming after %d", after)
+
+	err = handler.feed.Stream(ctx, after, func(event *repository.Event) error {
+		content, err := json.Marshal(outbox.NewMessage(event))
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, content); err != nil {
+			return err
+		}
+
+		flusher.Flush()
+
+		return nil
+	}, func() error {
+		if _, err := fmt.Fprint(writer, ": heartbeat\n\n"); err != nil {
+			return err
+		}
+
+		flusher.Flush()
+
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		handler.logger.Error(err)
+	}
+}
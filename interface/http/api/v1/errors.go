@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/go-http-utils/headers"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/ldez/mimetype"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DuplicateErrorCode code of Error when unique field of login is already taken
+	DuplicateErrorCode = "duplicate"
+	// ReservedErrorCode code of Error when login is reserved
+	ReservedErrorCode = "reserved"
+	// CooldownErrorCode code of Error when login is former login of another login during cooldown
+	CooldownErrorCode = "cooldown"
+	// InvalidErrorCode code of Error when fields of request violate validation rules or login policy
+	InvalidErrorCode = "invalid"
+)
+
+// responseError aborting transaction of handler with status code of response and Error as its body if set
+type responseError struct {
+	status   int
+	apiError *Error
+}
+
+func (err *responseError) Error() string {
+	return http.StatusText(err.status)
+}
+
+// newDuplicateError returns Error with conflicting field if err is repository.DuplicateError
+func newDuplicateError(err error) (*Error, bool) {
+	duplicateError := &repository.DuplicateError{}
+	if !errors.As(err, &duplicateError) {
+		return nil, false
+	}
+
+	return &Error{Code: DuplicateErrorCode, Field: duplicateError.Field, Message: duplicateError.Error()}, true
+}
+
+// validate returns Error with field-level violations of validation rules and login policy, nil if login is valid
+func (handler *API) validate(login *Login) *Error {
+	var violations []*Violation
+
+	if err := handler.validator.Struct(login); err != nil {
+		validationErrors := validator.ValidationErrors{}
+		if !errors.As(err, &validationErrors) {
+			return &Error{Code: InvalidErrorCode, Message: err.Error()}
+		}
+
+		for _, fieldError := range validationErrors {
+			rule := fieldError.Tag()
+			if fieldError.Param() != "" {
+				rule = fmt.Sprintf("%s=%s", rule, fieldError.Param())
+			}
+
+			violations = append(violations, &Violation{
+				Field:   strings.ToLower(fieldError.Field()[:1]) + fieldError.Field()[1:],
+				Rule:    rule,
+				Message: fmt.Sprintf("must satisfy '%s'", rule),
+			})
+		}
+	}
+
+	for _, violation := range handler.policy.Check(login.Login) {
+		violations = append(violations, &Violation{Field: LoginFieldName, Rule: violation.Rule, Message: violation.Message})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{Code: InvalidErrorCode, Message: "request violates validation rules", Violations: violations}
+}
+
+// checkReserved returns Error if login is reserved
+func (handler *API) checkReserved(ctx context.Context, login string) (*Error, error) {
+	reserved, err := handler.repository.MatchReserved(ctx, login)
+	if err == db.RecordNotFoundError {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Error{
+		Code:    ReservedErrorCode,
+		Field:   LoginFieldName,
+		Message: fmt.Sprintf("login is reserved by '%s'", reserved.Pattern),
+	}, nil
+}
+
+// checkCooldown returns Error if login is held by cooldown for another owner
+func (handler *API) checkCooldown(ctx context.Context, login string, owner uuid.UUID) (*Error, error) {
+	alias, releasedAt, err := handler.cooldown.Held(ctx, login, owner)
+	if err != nil || alias == nil {
+		return nil, err
+	}
+
+	return &Error{
+		Code:    CooldownErrorCode,
+		Field:   LoginFieldName,
+		Message: fmt.Sprintf("login was renamed and is released at %s", releasedAt.Format(time.RFC3339)),
+	}, nil
+}
+
+// writeResponseError writing responseError as json Error if set, as status text otherwise
+func (handler *API) writeResponseError(writer http.ResponseWriter, err *responseError) {
+	if err.apiError != nil {
+		handler.writeError(writer, err.status, err.apiError)
+		return
+	}
+
+	http.Error(writer, http.StatusText(err.status), err.status)
+}
+
+// writeError writing Error as json body of response with status code
+func (handler *API) writeError(writer http.ResponseWriter, statusCode int, apiError *Error) {
+	content, err := json.Marshal(apiError)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.WriteHeader(statusCode)
+
+	if _, err := writer.Write(content); err != nil {
+		handler.logger.Error(err)
+	}
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/policy"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/go-playground/validator/v10"
+	"strings"
+	"testing"
+)
+
+// testLogger writing to log of test
+type testLogger struct {
+	t *testing.T
+}
+
+func (logger testLogger) Debugf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Debug(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Infof(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Info(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Warnf(format string, args ...interface{})  { logger.t.Logf(format, args...) }
+func (logger testLogger) Warn(args ...interface{})                  { logger.t.Log(args...) }
+func (logger testLogger) Print(args ...interface{})                 { logger.t.Log(args...) }
+func (logger testLogger) Errorf(format string, args ...interface{}) { logger.t.Logf(format, args...) }
+func (logger testLogger) Error(args ...interface{})                 { logger.t.Log(args...) }
+
+func TestAPI_Validate(t *testing.T) {
+	loginPolicy, err := policy.NewLoginPolicy(&policy.Config{
+		MinLength: policy.MinLengthDefault,
+		MaxLength: policy.MaxLengthDefault,
+		Classes:   policy.ClassesDefault,
+	}, testLogger{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &API{validator: validator.New(), policy: loginPolicy}
+
+	apiError := handler.validate(&Login{Login: "alice", BanReason: strings.Repeat("a", 65)})
+	if apiError == nil || apiError.Code != InvalidErrorCode || len(apiError.Violations) != 1 {
+		t.Fatalf("error of too long ban reason %+v, expected one violation", apiError)
+	}
+
+	if violation := apiError.Violations[0]; violation.Field != "banReason" || violation.Rule != "max=64" {
+		t.Fatalf("violation of field '%s' of rule '%s', expected 'banReason' of 'max=64'", violation.Field, violation.Rule)
+	}
+
+	if apiError := handler.validate(&Login{}); apiError == nil || apiError.Violations[0].Rule != "required" {
+		t.Fatalf("error of empty login %+v, expected violation of 'required'", apiError)
+	}
+
+	if apiError := handler.validate(&Login{Login: "alice"}); apiError != nil {
+		t.Fatalf("error of valid login %+v", apiError)
+	}
+}
+
+func TestNewDuplicateError(t *testing.T) {
+	apiError, ok := newDuplicateError(fmt.Errorf("insert: %w", repository.ErrDuplicateLogin))
+	if !ok {
+		t.Fatal("wrapped duplicate error isn't recognized")
+	}
+
+	if apiError.Code != DuplicateErrorCode || apiError.Field != LoginFieldName {
+		t.Fatalf("error %+v, expected duplicate of login", apiError)
+	}
+
+	if _, ok := newDuplicateError(repository.VersionConflictError); ok {
+		t.Fatal("version conflict is recognized as duplicate")
+	}
+}
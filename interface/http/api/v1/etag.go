@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/go-http-utils/headers"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	InvalidIfMatchError = errors.New("if-match must be a single strong entity tag or '*'")
+)
+
+// etag returns entity tag of login version
+func etag(login *repository.Login) string {
+	return strconv.Quote(strconv.FormatInt(login.Version, 10))
+}
+
+// ifMatch returns login version expected by If-Match header, zero if the header is absent or '*'
+func ifMatch(request *http.Request) (int64, error) {
+	value := strings.TrimSpace(request.Header.Get(headers.IfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", InvalidIfMatchError, value)
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: %s", InvalidIfMatchError, value)
+	}
+
+	return version, nil
+}
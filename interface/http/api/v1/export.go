@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"context"
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/go-http-utils/headers"
+	"github.com/ldez/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+)
+
+// flusherContextKey key of http.Flusher of connection of request in context
+type flusherContextKey struct{}
+
+// WithFlusher returns copy of ctx with flusher of connection of request
+func WithFlusher(ctx context.Context, flusher http.Flusher) context.Context {
+	return context.WithValue(ctx, flusherContextKey{}, flusher)
+}
+
+// flusherOf returns http.Flusher of writer, or the one of ctx set by WithFlusher
+// when middlewares wrap writer without forwarding of Flush
+func flusherOf(ctx context.Context, writer http.ResponseWriter) (http.Flusher, bool) {
+	if flusher, ok := writer.(http.Flusher); ok {
+		return flusher, true
+	}
+
+	flusher, ok := ctx.Value(flusherContextKey{}).(http.Flusher)
+
+	return flusher, ok
+}
+
+// startedWriter http.ResponseWriter remembering whether anything was written to the client
+type startedWriter struct {
+	http.ResponseWriter
+	flusher http.Flusher
+	started bool
+}
+
+func (writer *startedWriter) Write(content []byte) (int, error) {
+	writer.started = true
+
+	return writer.ResponseWriter.Write(content)
+}
+
+func (writer *startedWriter) Flush() {
+	if writer.flusher != nil {
+		writer.flusher.Flush()
+	}
+}
+
+// Export streaming every login matched by filter in ndjson or csv
+func (handler *API) Export(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Export")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	format := ctx.Value(FormatFieldName).(string)
+	if err := exporter.CheckFormat(format); err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	loginsFilter, err := filter(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	if format == exporter.CSVFormat {
+		writer.Header().Set(headers.ContentType, mimetype.TextCsv)
+	} else {
+		writer.Header().Set(headers.ContentType, NDJSONContentType)
+	}
+
+	flusher, _ := flusherOf(ctx, writer)
+	started := &startedWriter{ResponseWriter: writer, flusher: flusher}
+
+	if _, err := handler.exporter.Export(ctx, started, format, loginsFilter); err != nil {
+		if !started.started {
+			http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+
+		handler.logger.Error(err)
+	}
+}
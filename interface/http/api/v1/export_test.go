@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// hidingWriter wrapper of response writer without forwarding of Flush, like the ones of middlewares
+type hidingWriter struct {
+	http.ResponseWriter
+}
+
+func TestFlusherOf(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer := &hidingWriter{ResponseWriter: recorder}
+
+	if _, ok := flusherOf(context.Background(), writer); ok {
+		t.Fatal("flusher of wrapper without Flush is found without context")
+	}
+
+	flusher, ok := flusherOf(WithFlusher(context.Background(), recorder), writer)
+	if !ok {
+		t.Fatal("flusher of context isn't found")
+	}
+
+	flusher.Flush()
+
+	if !recorder.Flushed {
+		t.Fatal("flusher of context doesn't flush response")
+	}
+
+	if flusher, ok := flusherOf(context.Background(), recorder); !ok || flusher != recorder {
+		t.Fatal("flusher of writer isn't found")
+	}
+}
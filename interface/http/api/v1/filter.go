@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filter returns repository.Filter of request placed to ctx by filtering middlewares
+func filter(ctx context.Context) (*repository.Filter, error) {
+	filter := &repository.Filter{
+		LoginPrefix: ctx.Value(LoginPrefixFieldName).(string),
+		Sort:        ctx.Value(SortFieldName).(string),
+	}
+
+	if value := ctx.Value(BannedFieldName).(string); value != "" {
+		banned, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false", repository.InvalidFilterError, BannedFieldName)
+		}
+
+		filter.Banned = &banned
+	}
+
+	times := map[string]**time.Time{
+		CreatedFromFieldName: &filter.CreatedFrom,
+		CreatedToFieldName:   &filter.CreatedTo,
+		UpdatedFromFieldName: &filter.UpdatedFrom,
+		UpdatedToFieldName:   &filter.UpdatedTo,
+	}
+
+	for name, field := range times {
+		value := ctx.Value(name).(string)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be RFC 3339 time", repository.InvalidFilterError, name)
+		}
+
+		*field = &parsed
+	}
+
+	if value := ctx.Value(MetadataFieldName).(string); value != "" {
+		index := strings.Index(value, ":")
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s must be key:value", repository.InvalidFilterError, MetadataFieldName)
+		}
+
+		filter.MetadataKey = value[:index]
+		filter.MetadataValue = value[index+1:]
+	}
+
+	switch ctx.Value(DirectionFieldName).(string) {
+	case AscDirection:
+	case DescDirection:
+		filter.Desc = true
+	default:
+		return nil, fmt.Errorf("%w: %s must be %s or %s", repository.InvalidFilterError, DirectionFieldName, AscDirection, DescDirection)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"encoding/json"
+	"github.com/go-http-utils/headers"
+	"net/http"
+	"strings"
+)
+
+// updateMetadata returns metadata of request replacing stored one,
+// or stored metadata with applied merge patch if request has MergePatchContentType
+func (handler *API) updateMetadata(request *http.Request, stored string, metadata json.RawMessage) (string, error) {
+	if strings.HasPrefix(request.Header.Get(headers.ContentType), MergePatchContentType) {
+		return handler.limiter.Merge(stored, metadata)
+	}
+
+	return handler.limiter.Normalize(metadata)
+}
+
+// newMetadataError returns Error of invalid or too large metadata
+func newMetadataError(err error) *Error {
+	return &Error{Code: InvalidErrorCode, Field: MetadataFieldName, Message: err.Error()}
+}
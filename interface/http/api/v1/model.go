@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"encoding/json"
+	"github.com/Diez37/logins/infrastructure/repository"
 	"github.com/google/uuid"
 	"time"
 )
@@ -10,16 +12,162 @@ type Page struct {
 	Records []*Login `json:"records"`
 }
 
+// RevisionPage logins changed after revision of request in order of their revisions,
+// Revision is the revision to request the next changes after
+type RevisionPage struct {
+	Revision int64    `json:"revision"`
+	More     bool     `json:"more"`
+	Records  []*Login `json:"records"`
+}
+
 type Meta struct {
-	Count int64 `json:"count"`
-	Page  uint  `json:"page"`
-	Limit uint  `json:"limit"`
+	Count int64  `json:"count"`
+	Page  uint   `json:"page"`
+	Limit uint   `json:"limit"`
+	Next  string `json:"next,omitempty"`
+}
+
+type Error struct {
+	Code       string       `json:"code"`
+	Field      string       `json:"field,omitempty"`
+	Message    string       `json:"message"`
+	Violations []*Violation `json:"violations,omitempty"`
+}
+
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 type Login struct {
+	Uuid        uuid.UUID       `json:"uuid" validate:"-"`
+	Tenant      string          `json:"tenant" validate:"-"`
+	Login       string          `json:"login" validate:"required"`
+	Banned      *bool           `json:"banned" validate:"-"`
+	BannedUntil *time.Time      `json:"bannedUntil" validate:"-"`
+	BanReason   string          `json:"banReason" validate:"max=64"`
+	CreatedAt   *time.Time      `json:"createdAt" validate:"-"`
+	UpdateAt    *time.Time      `json:"updateAt" validate:"-"`
+	Revision    int64           `json:"revision" validate:"-"`
+	Metadata    json.RawMessage `json:"metadata" validate:"-"`
+}
+
+type Ban struct {
+	By     string     `json:"by" validate:"max=128"`
+	Until  *time.Time `json:"until" validate:"-"`
+	Reason string     `json:"reason" validate:"max=64"`
+}
+
+type BanPage struct {
+	Meta    *Meta        `json:"meta"`
+	Records []*BanRecord `json:"records"`
+}
+
+type BanRecord struct {
+	Banned    bool       `json:"banned"`
+	By        string     `json:"by"`
+	Reason    string     `json:"reason"`
+	Until     *time.Time `json:"until"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+type Reserved struct {
+	Pattern   string     `json:"pattern" validate:"required,max=255"`
+	Glob      bool       `json:"glob" validate:"-"`
+	CreatedAt *time.Time `json:"createdAt" validate:"-"`
+}
+
+type Webhook struct {
 	Uuid      uuid.UUID  `json:"uuid" validate:"-"`
-	Login     string     `json:"login" validate:"required"`
-	Banned    *bool      `json:"banned" validate:"-"`
+	Tenant    string     `json:"tenant" validate:"-"`
+	Url       string     `json:"url" validate:"required,url,max=2048"`
+	Secret    string     `json:"secret,omitempty" validate:"max=255"`
+	Events    []string   `json:"events" validate:"-"`
+	Active    *bool      `json:"active" validate:"-"`
 	CreatedAt *time.Time `json:"createdAt" validate:"-"`
 	UpdateAt  *time.Time `json:"updateAt" validate:"-"`
 }
+
+type DeliveryPage struct {
+	Meta    *Meta       `json:"meta"`
+	Records []*Delivery `json:"records"`
+}
+
+type Delivery struct {
+	Id             int64      `json:"id"`
+	Type           string     `json:"type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt"`
+	UpdateAt       *time.Time `json:"updateAt"`
+}
+
+func newLogin(login *repository.Login) *Login {
+	return &Login{
+		Uuid:        login.Uuid,
+		Tenant:      login.Tenant,
+		Login:       login.Login,
+		Banned:      &login.Banned,
+		BannedUntil: login.BannedUntil,
+		BanReason:   login.BanReason,
+		CreatedAt:   login.CreatedAt,
+		UpdateAt:    login.UpdateAt,
+		Revision:    login.Revision,
+		Metadata:    repository.RawMetadata(login),
+	}
+}
+
+func newBanRecord(record *repository.BanRecord) *BanRecord {
+	return &BanRecord{
+		Banned:    record.Banned,
+		By:        record.Actor,
+		Reason:    record.Reason,
+		Until:     record.Until,
+		CreatedAt: record.CreatedAt,
+	}
+}
+
+func newReserved(reserved *repository.Reserved) *Reserved {
+	return &Reserved{
+		Pattern:   reserved.Pattern,
+		Glob:      reserved.Glob,
+		CreatedAt: reserved.CreatedAt,
+	}
+}
+
+// newWebhook returns webhook of api, secret is shown only once on creation of webhook
+func newWebhook(webhook *repository.Webhook, withSecret bool) *Webhook {
+	record := &Webhook{
+		Uuid:      webhook.Uuid,
+		Tenant:    webhook.Tenant,
+		Url:       webhook.Url,
+		Events:    webhook.EventTypes(),
+		Active:    &webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdateAt:  webhook.UpdateAt,
+	}
+
+	if withSecret {
+		record.Secret = webhook.Secret
+	}
+
+	return record
+}
+
+func newDelivery(delivery *repository.Delivery) *Delivery {
+	return &Delivery{
+		Id:             delivery.Id,
+		Type:           delivery.Type,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		CreatedAt:      delivery.CreatedAt,
+		UpdateAt:       delivery.UpdateAt,
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"net/http"
+	"strconv"
+)
+
+var (
+	InvalidPaginationError = errors.New("page and limit must be greater than zero")
+	// InvalidSearchPaginationError returned when search is paginated by cursor, ranked results are paginated by page only
+	InvalidSearchPaginationError = errors.New("search doesn't support cursor pagination")
+	// InvalidDeliveriesPaginationError returned when deliveries of webhook are paginated by cursor
+	InvalidDeliveriesPaginationError = errors.New("deliveries don't support cursor pagination")
+	// InvalidRevisionPaginationError returned when changes since revision are paginated by page or cursor,
+	// the next changes are requested since the returned revision
+	InvalidRevisionPaginationError = errors.New("changes since revision don't support page and cursor pagination")
+)
+
+// pagination returns page, limit and cursor of request placed to ctx by pagination middlewares
+func pagination(ctx context.Context) (uint, uint, *repository.Cursor, error) {
+	page := uint(ctx.Value(PageFieldName).(uint64))
+	limit := uint(ctx.Value(LimitFieldName).(uint64))
+
+	if page < 1 || limit < 1 {
+		return 0, 0, nil, InvalidPaginationError
+	}
+
+	value := ctx.Value(CursorFieldName).(string)
+	if value == "" {
+		return page, limit, nil, nil
+	}
+
+	cursor, err := repository.DecodeCursor(value)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	return page, limit, cursor, nil
+}
+
+func (meta *Meta) setHeaders(header http.Header) {
+	header.Set(CountHeaderName, strconv.FormatInt(meta.Count, 10))
+	header.Set(PageHeaderName, strconv.FormatUint(uint64(meta.Page), 10))
+	header.Set(LimitHeaderName, strconv.FormatUint(uint64(meta.Limit), 10))
+
+	if meta.Next != "" {
+		header.Set(NextHeaderName, meta.Next)
+	}
+}
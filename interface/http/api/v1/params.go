@@ -1,16 +1,75 @@
 package v1
 
+import (
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/Diez37/logins/infrastructure/repository"
+)
+
 const (
-	UuidFieldName  = "uuid"
-	LoginFieldName = "login"
+	UuidFieldName    = "uuid"
+	LoginFieldName   = "login"
+	PatternFieldName = "pattern"
+	UrlFieldName     = "url"
+	EventsFieldName  = "events"
+	TenantFieldName  = "tenant"
+	// MetadataFieldName name of metadata of login and of its filter by value of key given as key:value
+	MetadataFieldName = "metadata"
+
+	PageFieldName   = "page"
+	LimitFieldName  = "limit"
+	CursorFieldName = "cursor"
+
+	QueryFieldName = "q"
+	ModeFieldName  = "mode"
+
+	FormatFieldName = "format"
+
+	LastEventIdFieldName = "last_event_id"
+
+	SinceRevisionFieldName = "since_revision"
 
-	PageFieldName  = "page"
-	LimitFieldName = "limit"
+	BannedFieldName      = "banned"
+	CreatedFromFieldName = "created_from"
+	CreatedToFieldName   = "created_to"
+	UpdatedFromFieldName = "updated_from"
+	UpdatedToFieldName   = "updated_to"
+	LoginPrefixFieldName = "login_prefix"
+	SortFieldName        = "sort"
+	DirectionFieldName   = "direction"
+
+	AscDirection  = "asc"
+	DescDirection = "desc"
 
 	CountHeaderName = "X-Pagination-Count"
 	PageHeaderName  = "X-Pagination-Page"
 	LimitHeaderName = "X-Pagination-Limit"
+	NextHeaderName  = "X-Pagination-Next"
+
+	RenamedFromHeaderName = "X-Login-Renamed-From"
+	SessionHeaderName     = "X-Session-Id"
+	TenantHeaderName      = "X-Tenant"
+	LastEventIdHeaderName = "Last-Event-ID"
+	RevisionHeaderName    = "X-Revision"
+
+	// NDJSONContentType content type of newline delimited json
+	NDJSONContentType = "application/x-ndjson"
+	// EventStreamContentType content type of server-sent events
+	EventStreamContentType = "text/event-stream"
+	// MergePatchContentType content type of json merge patch (RFC 7396) applied to metadata of login on update
+	MergePatchContentType = "application/merge-patch+json"
+
+	LimitDefault  = uint64(20)
+	PageDefault   = uint64(1)
+	CursorDefault = ""
+	QueryDefault  = ""
+	ModeDefault   = repository.PrefixSearchMode
+	FormatDefault = exporter.NDJSONFormat
+
+	LastEventIdDefault = ""
+
+	SinceRevisionDefault = ""
 
-	LimitDefault = uint(20)
-	PageDefault  = uint(1)
+	FilterDefault    = ""
+	SortDefault      = repository.IdSort
+	DirectionDefault = AscDirection
 )
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/go-http-utils/headers"
+	"github.com/ldez/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"io/ioutil"
+	"net/http"
+)
+
+func (handler *API) ListReserved(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "ListReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	reservations, err := handler.repository.ListReserved(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	records := make([]*Reserved, len(reservations))
+	for index, reserved := range reservations {
+		records[index] = newReserved(reserved)
+	}
+
+	content, err := json.Marshal(records)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
+
+func (handler *API) AddReserved(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "AddReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	reserved := Reserved{}
+	if err := json.Unmarshal(body, &reserved); err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	if err := handler.validator.Struct(reserved); err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	reservedForRepository, err := repository.NewReserved(reserved.Pattern)
+	if err != nil {
+		handler.writeError(writer, http.StatusBadRequest, &Error{Code: InvalidErrorCode, Field: PatternFieldName, Message: err.Error()})
+		return
+	}
+
+	reservedForRepository, err = handler.repository.AddReserved(ctx, reservedForRepository)
+	if apiError, ok := newDuplicateError(err); ok {
+		handler.writeError(writer, http.StatusConflict, apiError)
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	handler.logger.Infof("api:v1:reserved: added '%s'", reservedForRepository.Pattern)
+
+	content, err := json.Marshal(newReserved(reservedForRepository))
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
+
+func (handler *API) RemoveReserved(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "RemoveReserved")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	pattern := ctx.Value(PatternFieldName).(string)
+
+	err := handler.repository.RemoveReserved(ctx, pattern)
+	if errors.Is(err, db.RecordNotFoundError) {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	handler.logger.Infof("api:v1:reserved: removed '%s'", pattern)
+
+	writer.WriteHeader(http.StatusOK)
+}
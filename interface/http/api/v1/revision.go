@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/go-http-utils/headers"
+	"github.com/ldez/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// changedSince writing up to limit logins changed after revision since with revision to request the next changes after,
+// filters and sort of listing don't apply: changes are ordered by their revisions
+func (handler *API) changedSince(writer http.ResponseWriter, request *http.Request, since string, limit uint) {
+	ctx, span := handler.tracer.Start(request.Context(), "changedSince")
+	defer span.End()
+
+	revision, err := strconv.ParseInt(since, 10, 64)
+	if err == nil && revision < 0 {
+		err = fmt.Errorf("api:v1:page: negative %s %d", SinceRevisionFieldName, revision)
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("since", revision))
+
+	var latest int64
+	var models []*repository.Login
+
+	err = handler.repository.Snapshot(ctx, func(tx repository.Repository) error {
+		latest, err = tx.Revision(ctx)
+		if err != nil {
+			return err
+		}
+
+		models, err = tx.ChangedSince(ctx, revision, latest, limit)
+
+		return err
+	})
+	if err != nil && err != io.EOF {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	// revision of request ahead of the latest one is kept, so the client doesn't go back
+	if latest < revision {
+		latest = revision
+	}
+
+	more := uint(len(models)) == limit
+	if more {
+		latest = models[len(models)-1].Revision
+	}
+
+	logins := make([]*Login, len(models))
+	for index, login := range models {
+		logins[index] = newLogin(login)
+	}
+
+	content, err := json.Marshal(&RevisionPage{
+		Revision: latest,
+		More:     more,
+		Records:  logins,
+	})
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	writer.Header().Set(RevisionHeaderName, strconv.FormatInt(latest, 10))
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
@@ -0,0 +1,339 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/webhook"
+	"github.com/diez37/go-packages/clients/db"
+	"github.com/go-http-utils/headers"
+	"github.com/google/uuid"
+	"github.com/ldez/mimetype"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// checkWebhook returns Error of url with scheme other than http or https and of unknown types of events
+func checkWebhook(subscription *Webhook) *Error {
+	parsed, err := url.Parse(subscription.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return &Error{Code: InvalidErrorCode, Field: UrlFieldName, Message: "url must be absolute http or https url"}
+	}
+
+	if err := webhook.CheckEvents(subscription.Events); err != nil {
+		return &Error{Code: InvalidErrorCode, Field: EventsFieldName, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func (handler *API) ListWebhooks(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "ListWebhooks")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	webhooks, err := handler.repository.ListWebhooks(ctx)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	records := make([]*Webhook, len(webhooks))
+	for index, webhookFromRepository := range webhooks {
+		records[index] = newWebhook(webhookFromRepository, false)
+	}
+
+	handler.writeWebhook(writer, records)
+}
+
+func (handler *API) FindWebhook(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "FindWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	webhookFromRepository, err := handler.repository.FindWebhook(ctx, ctx.Value(UuidFieldName).(uuid.UUID))
+	if errors.Is(err, db.RecordNotFoundError) {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	handler.writeWebhook(writer, newWebhook(webhookFromRepository, false))
+}
+
+func (handler *API) AddWebhook(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "AddWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	subscription, ok := handler.readWebhook(writer, request)
+	if !ok {
+		return
+	}
+
+	webhookForRepository := &repository.Webhook{
+		Url:    subscription.Url,
+		Secret: subscription.Secret,
+		Events: repository.JoinEvents(subscription.Events),
+		Active: subscription.Active == nil || *subscription.Active,
+	}
+
+	if webhookForRepository.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			handler.logger.Error(err)
+			return
+		}
+
+		webhookForRepository.Secret = secret
+	}
+
+	webhookForRepository, err := handler.repository.AddWebhook(ctx, webhookForRepository)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	handler.logger.Infof("api:v1:webhooks: added '%s', url '%s'", webhookForRepository.Uuid.String(), webhookForRepository.Url)
+
+	handler.writeWebhook(writer, newWebhook(webhookForRepository, true))
+}
+
+// UpdateWebhook replacing url of webhook, empty secret, missing events and activity are left as is
+func (handler *API) UpdateWebhook(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "UpdateWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	subscription, ok := handler.readWebhook(writer, request)
+	if !ok {
+		return
+	}
+
+	var webhookFromRepository *repository.Webhook
+
+	err := handler.repository.Transaction(ctx, func(tx repository.Repository) error {
+		found, err := tx.FindWebhook(ctx, ctx.Value(UuidFieldName).(uuid.UUID))
+		if err != nil {
+			return err
+		}
+
+		found.Url = subscription.Url
+
+		if subscription.Secret != "" {
+			found.Secret = subscription.Secret
+		}
+
+		if subscription.Events != nil {
+			found.Events = repository.JoinEvents(subscription.Events)
+		}
+
+		if subscription.Active != nil {
+			found.Active = *subscription.Active
+		}
+
+		webhookFromRepository, err = tx.UpdateWebhook(ctx, found)
+
+		return err
+	})
+	if errors.Is(err, db.RecordNotFoundError) {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	handler.logger.Infof("api:v1:webhooks: updated '%s'", webhookFromRepository.Uuid.String())
+
+	handler.writeWebhook(writer, newWebhook(webhookFromRepository, false))
+}
+
+func (handler *API) RemoveWebhook(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "RemoveWebhook")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	webhookUuid := ctx.Value(UuidFieldName).(uuid.UUID)
+
+	err := handler.repository.RemoveWebhook(ctx, webhookUuid)
+	if errors.Is(err, db.RecordNotFoundError) {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	handler.logger.Infof("api:v1:webhooks: removed '%s'", webhookUuid.String())
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+// Deliveries paging log of deliveries of webhook from the latest
+func (handler *API) Deliveries(writer http.ResponseWriter, request *http.Request) {
+	ctx, span := handler.tracer.Start(request.Context(), "Deliveries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("interface", "http"),
+		attribute.String("handler", "api.v1"),
+	)
+
+	page, limit, cursor, err := pagination(ctx)
+	if err == nil && cursor != nil {
+		err = InvalidDeliveriesPaginationError
+	}
+
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return
+	}
+
+	webhookUuid := ctx.Value(UuidFieldName).(uuid.UUID)
+
+	var totalCount int64
+	var models []*repository.Delivery
+
+	err = handler.repository.Snapshot(ctx, func(tx repository.Repository) error {
+		if _, err := tx.FindWebhook(ctx, webhookUuid); err != nil {
+			return err
+		}
+
+		count, err := tx.CountDeliveries(ctx, webhookUuid)
+		if err != nil {
+			return err
+		}
+
+		totalCount = count
+
+		models, err = tx.PageDeliveries(ctx, webhookUuid, page-1, limit)
+
+		return err
+	})
+	if errors.Is(err, db.RecordNotFoundError) {
+		http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err != nil && err != io.EOF {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	records := make([]*Delivery, len(models))
+	for index, delivery := range models {
+		records[index] = newDelivery(delivery)
+	}
+
+	meta := &Meta{
+		Count: totalCount,
+		Page:  page,
+		Limit: limit,
+	}
+
+	content, err := json.Marshal(&DeliveryPage{
+		Meta:    meta,
+		Records: records,
+	})
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	meta.setHeaders(writer.Header())
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
+
+// readWebhook returns validated webhook of body of request, writes error response if it's invalid
+func (handler *API) readWebhook(writer http.ResponseWriter, request *http.Request) (*Webhook, bool) {
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return nil, false
+	}
+
+	subscription := &Webhook{}
+	if err := json.Unmarshal(body, subscription); err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return nil, false
+	}
+
+	if err := handler.validator.Struct(subscription); err != nil {
+		http.Error(writer, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		handler.logger.Error(err)
+		return nil, false
+	}
+
+	if apiError := checkWebhook(subscription); apiError != nil {
+		handler.writeError(writer, http.StatusBadRequest, apiError)
+		return nil, false
+	}
+
+	return subscription, true
+}
+
+func (handler *API) writeWebhook(writer http.ResponseWriter, record interface{}) {
+	content, err := json.Marshal(record)
+	if err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+		return
+	}
+
+	writer.Header().Add(headers.ContentType, mimetype.ApplicationJSON)
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err := writer.Write(content); err != nil {
+		http.Error(writer, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		handler.logger.Error(err)
+	}
+}
@@ -2,7 +2,17 @@ package http
 
 import (
 	"context"
+	"github.com/Diez37/logins/infrastructure/alias"
+	"github.com/Diez37/logins/infrastructure/changes"
+	"github.com/Diez37/logins/infrastructure/database"
+	"github.com/Diez37/logins/infrastructure/expirer"
+	"github.com/Diez37/logins/infrastructure/exporter"
+	"github.com/Diez37/logins/infrastructure/importer"
+	"github.com/Diez37/logins/infrastructure/metadata"
+	"github.com/Diez37/logins/infrastructure/outbox"
+	"github.com/Diez37/logins/infrastructure/policy"
 	"github.com/Diez37/logins/infrastructure/repository"
+	"github.com/Diez37/logins/infrastructure/webhook"
 	"github.com/Diez37/logins/interface/http/api"
 	"github.com/diez37/go-packages/container"
 	"github.com/diez37/go-packages/log"
@@ -29,14 +39,33 @@ func Serve(ctx context.Context, container container.Container, logger log.Logger
 		tracer trace.Tracer,
 		router chi.Router,
 		validator *validator.Validate,
+		expirer *expirer.Expirer,
+		policy *policy.LoginPolicy,
+		cooldown *alias.Cooldown,
+		limiter *metadata.Limiter,
+		importer *importer.Importer,
+		exporter *exporter.Exporter,
+		relay *outbox.Relay,
+		dispatcher *webhook.Dispatcher,
+		feed *changes.Feed,
+		replicasConfig *database.ReplicasConfig,
 	) {
 		router.Mount("/api", api.Router(
 			repository,
 			tracer,
 			logger,
 			validator,
+			policy,
+			cooldown,
+			limiter,
+			importer,
+			exporter,
+			feed,
+			replicasConfig,
 		))
 
+		server.Handler = api.Flushing(server.Handler)
+
 		errGroup.Go(func() error {
 			defer cancelFunc()
 
@@ -53,6 +82,18 @@ func Serve(ctx context.Context, container container.Container, logger log.Logger
 			return nil
 		})
 
+		errGroup.Go(func() error {
+			return expirer.Run(ctx)
+		})
+
+		errGroup.Go(func() error {
+			return relay.Run(ctx)
+		})
+
+		errGroup.Go(func() error {
+			return dispatcher.Run(ctx)
+		})
+
 		errGroup.Go(func() error {
 			<-ctx.Done()
 
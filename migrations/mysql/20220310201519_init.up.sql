@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS logins
+(
+    id         BIGINT      NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    uuid       CHAR(36)    NOT NULL,
+    login      VARCHAR(56) NOT NULL,
+    banned     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_at  TIMESTAMP   NULL
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE UNIQUE INDEX logins_uuid ON logins (uuid);
+CREATE UNIQUE INDEX logins_login ON logins (login);
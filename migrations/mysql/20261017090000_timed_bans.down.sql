@@ -0,0 +1,4 @@
+DROP INDEX logins_banned_until ON logins;
+
+ALTER TABLE logins DROP COLUMN ban_reason;
+ALTER TABLE logins DROP COLUMN banned_until;
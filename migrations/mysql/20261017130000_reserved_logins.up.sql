@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS reserved_logins
+(
+    id         BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    pattern    VARCHAR(255) NOT NULL,
+    glob       BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE UNIQUE INDEX reserved_logins_pattern ON reserved_logins (pattern);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS login_aliases
+(
+    id              BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    login_uuid      CHAR(36)     NOT NULL,
+    login           VARCHAR(56)  NOT NULL,
+    login_canonical VARCHAR(255) NOT NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE INDEX login_aliases_login_canonical ON login_aliases (login_canonical, id);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id         BIGINT        NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    uuid       CHAR(36)      NOT NULL,
+    url        VARCHAR(2048) NOT NULL,
+    secret     VARCHAR(255)  NOT NULL,
+    events     VARCHAR(255)  NOT NULL DEFAULT '',
+    active     BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE UNIQUE INDEX webhooks_uuid ON webhooks (uuid);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGINT      NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    webhook_uuid    CHAR(36)    NOT NULL,
+    type            VARCHAR(32) NOT NULL,
+    payload         TEXT        NOT NULL,
+    status          VARCHAR(16) NOT NULL,
+    attempts        INT         NOT NULL DEFAULT 0,
+    response_status INT         NOT NULL DEFAULT 0,
+    error           TEXT        NOT NULL,
+    next_attempt_at TIMESTAMP   NULL,
+    created_at      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_at       TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+CREATE INDEX webhook_deliveries_webhook_uuid ON webhook_deliveries (webhook_uuid, id);
+CREATE INDEX webhook_deliveries_status ON webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,9 @@
+DROP INDEX login_aliases_tenant_login_canonical ON login_aliases;
+CREATE INDEX login_aliases_login_canonical ON login_aliases (login_canonical, id);
+
+ALTER TABLE login_aliases DROP COLUMN tenant;
+
+DROP INDEX logins_tenant_login_canonical ON logins;
+CREATE UNIQUE INDEX logins_login_canonical ON logins (login_canonical);
+
+ALTER TABLE logins DROP COLUMN tenant;
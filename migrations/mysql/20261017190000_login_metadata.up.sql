@@ -0,0 +1,6 @@
+-- json object of arbitrary attributes of login, TEXT can't have default, so it's backfilled before NOT NULL
+ALTER TABLE logins ADD COLUMN metadata TEXT NULL;
+
+UPDATE logins SET metadata = '{}';
+
+ALTER TABLE logins MODIFY metadata TEXT NOT NULL;
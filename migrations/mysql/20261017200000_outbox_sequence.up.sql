@@ -0,0 +1,9 @@
+-- sequence of event is taken from the outbox row of sequences in the transaction of the event, the row stays locked
+-- until commit, so sequences become visible in their order without gaps, existing events are backfilled with their ids
+ALTER TABLE outbox ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0;
+
+UPDATE outbox SET sequence = id;
+
+CREATE INDEX outbox_sequence ON outbox (sequence);
+
+INSERT INTO sequences (name, value) SELECT 'outbox', COALESCE(MAX(id), 0) FROM outbox;
@@ -0,0 +1,9 @@
+-- positions of consumers of outbox in sequence of events, deliveries to webhooks are scheduled from events
+-- after the webhooks cursor, which starts after events of deliveries scheduled before
+CREATE TABLE IF NOT EXISTS outbox_cursors
+(
+    consumer VARCHAR(32) NOT NULL PRIMARY KEY,
+    sequence BIGINT      NOT NULL DEFAULT 0
+) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+
+INSERT INTO outbox_cursors (consumer, sequence) SELECT 'webhooks', value FROM sequences WHERE name = 'outbox';
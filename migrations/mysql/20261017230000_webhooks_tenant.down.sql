@@ -0,0 +1,3 @@
+DROP INDEX webhooks_tenant ON webhooks;
+
+ALTER TABLE webhooks DROP COLUMN tenant;
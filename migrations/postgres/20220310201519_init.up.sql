@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS logins
+(
+    id         BIGSERIAL PRIMARY KEY,
+    uuid       CHAR(36)    NOT NULL,
+    login      VARCHAR(56) NOT NULL,
+    banned     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_at  TIMESTAMP   NULL
+);
+
+CREATE UNIQUE INDEX logins_uuid ON logins (uuid);
+CREATE UNIQUE INDEX logins_login ON logins (login);
@@ -0,0 +1,4 @@
+ALTER TABLE logins ADD COLUMN banned_until TIMESTAMP NULL;
+ALTER TABLE logins ADD COLUMN ban_reason VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX logins_banned_until ON logins (banned_until);
@@ -0,0 +1 @@
+ALTER TABLE logins ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS reserved_logins
+(
+    id         BIGSERIAL PRIMARY KEY,
+    pattern    VARCHAR(255) NOT NULL,
+    glob       BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX reserved_logins_pattern ON reserved_logins (pattern);
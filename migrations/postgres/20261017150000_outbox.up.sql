@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           BIGSERIAL PRIMARY KEY,
+    type         VARCHAR(32) NOT NULL,
+    login_uuid   CHAR(36)    NOT NULL,
+    payload      TEXT        NOT NULL,
+    created_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP   NULL
+);
+
+CREATE INDEX outbox_published_at ON outbox (published_at, id);
@@ -0,0 +1,15 @@
+-- revision of login is taken from the logins row of sequences by every write of login,
+-- existing logins are backfilled with their ids
+ALTER TABLE logins ADD COLUMN revision BIGINT NOT NULL DEFAULT 0;
+
+UPDATE logins SET revision = id;
+
+CREATE INDEX logins_revision ON logins (revision);
+
+CREATE TABLE IF NOT EXISTS sequences
+(
+    name  VARCHAR(32) NOT NULL PRIMARY KEY,
+    value BIGINT      NOT NULL DEFAULT 0
+);
+
+INSERT INTO sequences (name, value) SELECT 'logins', COALESCE(MAX(id), 0) FROM logins;
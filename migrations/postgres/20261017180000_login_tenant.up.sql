@@ -0,0 +1,10 @@
+-- login is unique inside of its tenant, existing logins and aliases belong to the default tenant
+ALTER TABLE logins ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX logins_login_canonical;
+CREATE UNIQUE INDEX logins_tenant_login_canonical ON logins (tenant, login_canonical);
+
+ALTER TABLE login_aliases ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX login_aliases_login_canonical;
+CREATE INDEX login_aliases_tenant_login_canonical ON login_aliases (tenant, login_canonical, id);
@@ -0,0 +1,2 @@
+-- json object of arbitrary attributes of login
+ALTER TABLE logins ADD COLUMN metadata TEXT NOT NULL DEFAULT '{}';
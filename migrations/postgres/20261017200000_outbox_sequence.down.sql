@@ -0,0 +1,5 @@
+DELETE FROM sequences WHERE name = 'outbox';
+
+DROP INDEX outbox_sequence;
+
+ALTER TABLE outbox DROP COLUMN sequence;
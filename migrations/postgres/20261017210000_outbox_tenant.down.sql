@@ -0,0 +1,4 @@
+DROP INDEX outbox_tenant_sequence;
+CREATE INDEX outbox_sequence ON outbox (sequence);
+
+ALTER TABLE outbox DROP COLUMN tenant;
@@ -0,0 +1,3 @@
+DROP INDEX webhooks_tenant;
+
+ALTER TABLE webhooks DROP COLUMN tenant;
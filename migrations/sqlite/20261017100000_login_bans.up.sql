@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS login_bans
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    login_uuid   CHAR(36)     NOT NULL,
+    banned       BIT          NOT NULL,
+    actor        VARCHAR(128) NOT NULL DEFAULT '',
+    reason       VARCHAR(64)  NOT NULL DEFAULT '',
+    banned_until TIMESTAMP    NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX login_bans_login_uuid ON login_bans (login_uuid, id);
@@ -0,0 +1 @@
+ALTER TABLE logins ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- canonical form is NFKC case folded login computed by the application, so the column is backfilled
+-- by the migrator after this migration and the unique index is moved onto it by the next one
+ALTER TABLE logins ADD COLUMN login_canonical VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX logins_login_canonical;
+CREATE UNIQUE INDEX logins_login ON logins (login);
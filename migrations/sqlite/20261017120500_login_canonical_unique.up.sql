@@ -0,0 +1,2 @@
+DROP INDEX logins_login;
+CREATE UNIQUE INDEX logins_login_canonical ON logins (login_canonical);
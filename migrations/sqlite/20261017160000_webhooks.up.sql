@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    uuid       CHAR(36)      NOT NULL,
+    url        VARCHAR(2048) NOT NULL,
+    secret     VARCHAR(255)  NOT NULL,
+    events     VARCHAR(255)  NOT NULL DEFAULT '',
+    active     BIT           NOT NULL DEFAULT 1,
+    created_at TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX webhooks_uuid ON webhooks (uuid);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_uuid    CHAR(36)    NOT NULL,
+    type            VARCHAR(32) NOT NULL,
+    payload         TEXT        NOT NULL,
+    status          VARCHAR(16) NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    response_status INTEGER     NOT NULL DEFAULT 0,
+    error           TEXT        NOT NULL,
+    next_attempt_at TIMESTAMP   NULL,
+    created_at      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_at       TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX webhook_deliveries_webhook_uuid ON webhook_deliveries (webhook_uuid, id);
+CREATE INDEX webhook_deliveries_status ON webhook_deliveries (status, next_attempt_at);
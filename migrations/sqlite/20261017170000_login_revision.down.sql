@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS sequences;
+
+DROP INDEX logins_revision;
+
+ALTER TABLE logins DROP COLUMN revision;
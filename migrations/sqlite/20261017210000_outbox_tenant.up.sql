@@ -0,0 +1,7 @@
+-- events are streamed by tenants of their logins
+ALTER TABLE outbox ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+UPDATE outbox SET tenant = COALESCE((SELECT logins.tenant FROM logins WHERE logins.uuid = outbox.login_uuid), 'default');
+
+DROP INDEX outbox_sequence;
+CREATE INDEX outbox_tenant_sequence ON outbox (tenant, sequence);
@@ -0,0 +1,9 @@
+-- positions of consumers of outbox in sequence of events, deliveries to webhooks are scheduled from events
+-- after the webhooks cursor, which starts after events of deliveries scheduled before
+CREATE TABLE IF NOT EXISTS outbox_cursors
+(
+    consumer VARCHAR(32) NOT NULL PRIMARY KEY,
+    sequence INTEGER     NOT NULL DEFAULT 0
+);
+
+INSERT INTO outbox_cursors (consumer, sequence) SELECT 'webhooks', value FROM sequences WHERE name = 'outbox';
@@ -0,0 +1,4 @@
+-- webhooks receive events of logins of their tenant, existing webhooks belong to the default tenant
+ALTER TABLE webhooks ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX webhooks_tenant ON webhooks (tenant, id);